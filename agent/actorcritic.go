@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+// ActorCriticAgent implements one-step advantage actor-critic. The actor keeps
+// softmax preferences over moves and the critic keeps a state-value estimate.
+type ActorCriticAgent struct {
+	BaseAgent
+	preferences map[string][]float64
+	values      map[string]float64
+	actorAlpha  float64
+	criticAlpha float64
+	gamma       float64
+	Evaluating  bool
+	rng         *rand.Rand
+}
+
+func NewActorCriticAgent(player int) *ActorCriticAgent {
+	return &ActorCriticAgent{
+		BaseAgent:   BaseAgent{Player: player, Relative: true},
+		preferences: make(map[string][]float64),
+		values:      make(map[string]float64),
+		actorAlpha:  0.05,
+		criticAlpha: 0.1,
+		gamma:       0.99,
+		Evaluating:  false,
+		rng:         newRand(),
+	}
+}
+
+// Seed fixes the random source moves are sampled from
+func (a *ActorCriticAgent) Seed(seed int64) {
+	a.rng.Seed(seed)
+}
+
+// ForSeat returns a view of the agent seated as player. Both views share the
+// actor's preferences and the critic's values.
+func (a *ActorCriticAgent) ForSeat(player int) Agent {
+	seat := *a
+	seat.Player = player
+	return &seat
+}
+
+func (a *ActorCriticAgent) GetPreferences(state string) []float64 {
+	if _, exists := a.preferences[state]; !exists {
+		a.preferences[state] = make([]float64, 9)
+	}
+	return a.preferences[state]
+}
+
+// GetValue returns the critic's estimate for a state
+func (a *ActorCriticAgent) GetValue(state string) float64 {
+	return a.values[state]
+}
+
+// Policy returns the softmax probability of each move, restricted to legal moves
+func (a *ActorCriticAgent) Policy(state string, moves []int) []float64 {
+	prefs := a.GetPreferences(state)
+	probs := make([]float64, 9)
+	if len(moves) == 0 {
+		return probs
+	}
+
+	// Subtract the max preference to keep exp from overflowing
+	maxPref := prefs[moves[0]]
+	for _, move := range moves[1:] {
+		maxPref = math.Max(maxPref, prefs[move])
+	}
+
+	sum := 0.0
+	for _, move := range moves {
+		probs[move] = math.Exp(prefs[move] - maxPref)
+		sum += probs[move]
+	}
+	for _, move := range moves {
+		probs[move] /= sum
+	}
+	return probs
+}
+
+// SetEvaluating turns exploration off (true) or back on (false)
+func (a *ActorCriticAgent) SetEvaluating(evaluating bool) {
+	a.Evaluating = evaluating
+}
+
+func (a *ActorCriticAgent) GetMove(board *game.Board, player int) int {
+	moves := board.GetAvailableMoves()
+	if len(moves) == 0 {
+		return -1
+	}
+
+	state := a.GetStateKey(board)
+	probs := a.Policy(state, moves)
+
+	if a.Evaluating {
+		bestMove := moves[0]
+		for _, move := range moves[1:] {
+			if probs[move] > probs[bestMove] {
+				bestMove = move
+			}
+		}
+		return bestMove
+	}
+
+	r := a.rng.Float64()
+	for _, move := range moves {
+		r -= probs[move]
+		if r <= 0 {
+			return move
+		}
+	}
+	return moves[len(moves)-1]
+}
+
+func (a *ActorCriticAgent) Learn(state string, action int, reward float64, newState string) {
+	var nextValue float64
+	if newState != "" {
+		if gameOver, _ := game.FromStateString(newState).IsGameOver(); !gameOver {
+			nextValue = a.values[newState]
+		}
+	}
+
+	// The TD error doubles as the advantage estimate for the actor
+	tdError := reward + a.gamma*nextValue - a.values[state]
+	a.values[state] += a.criticAlpha * tdError
+
+	moves := game.FromStateString(state).GetAvailableMoves()
+	probs := a.Policy(state, moves)
+	prefs := a.GetPreferences(state)
+	for _, move := range moves {
+		if move == action {
+			prefs[move] += a.actorAlpha * tdError * (1 - probs[move])
+		} else {
+			prefs[move] -= a.actorAlpha * tdError * probs[move]
+		}
+	}
+}
+
+func (a *ActorCriticAgent) Save(filename string) error {
+	return SaveActorCritic(filename+".actorcritic", a.preferences, a.values, a.Relative)
+}
+
+func (a *ActorCriticAgent) Load(filename string) error {
+	preferences, values, relative, err := LoadActorCritic(filename + ".actorcritic")
+	if err != nil {
+		return err
+	}
+	a.preferences = preferences
+	a.values = values
+	a.Relative = relative
+	return nil
+}
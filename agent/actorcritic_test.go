@@ -0,0 +1,113 @@
+package agent
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+func TestActorCriticPolicy(t *testing.T) {
+	tests := []struct {
+		name  string
+		board [][]int
+	}{
+		{
+			name: "empty board",
+			board: [][]int{
+				{0, 0, 0},
+				{0, 0, 0},
+				{0, 0, 0},
+			},
+		},
+		{
+			name: "partially filled board",
+			board: [][]int{
+				{1, 0, 2},
+				{0, 1, 0},
+				{2, 0, 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := NewActorCriticAgent(1)
+			b := game.NewBoard()
+			b.SetState(tt.board)
+
+			moves := b.GetAvailableMoves()
+			probs := agent.Policy(agent.GetStateKey(b), moves)
+
+			sum := 0.0
+			for pos, p := range probs {
+				if !b.IsEmpty(pos) && p != 0 {
+					t.Errorf("Policy()[%d] = %v for occupied cell, want 0", pos, p)
+				}
+				sum += p
+			}
+			if math.Abs(sum-1) > 1e-9 {
+				t.Errorf("Policy() sums to %v, want 1", sum)
+			}
+		})
+	}
+}
+
+func TestActorCriticLearn(t *testing.T) {
+	agent := NewActorCriticAgent(1)
+	b := game.NewBoard()
+	b.SetState([][]int{
+		{1, 1, 0},
+		{2, 2, 0},
+		{0, 0, 0},
+	})
+	state := agent.GetStateKey(b)
+	moves := b.GetAvailableMoves()
+
+	before := agent.Policy(state, moves)[2]
+
+	// Completing the top row wins; the critic and actor should both move toward it
+	b.MakeMove(2, 1)
+	agent.Learn(state, 2, 1.0, agent.GetStateKey(b))
+
+	if after := agent.Policy(state, moves)[2]; after <= before {
+		t.Errorf("Policy()[2] after winning move = %v, want > %v", after, before)
+	}
+	if value := agent.GetValue(state); value <= 0 {
+		t.Errorf("GetValue() after winning move = %v, want > 0", value)
+	}
+
+	agent.Evaluating = true
+	if move := agent.GetMove(game.FromStateString(state), 1); move != 2 {
+		t.Errorf("GetMove() while evaluating = %v, want 2", move)
+	}
+}
+
+func TestActorCriticSeats(t *testing.T) {
+	a := NewActorCriticAgent(1)
+	x, o := a.ForSeat(1).(*ActorCriticAgent), a.ForSeat(2).(*ActorCriticAgent)
+
+	// O with two in the top row sees the position X would see with two in a row
+	board := game.FromStateString("220110000")
+	if key := o.GetStateKey(board); key != "110220000" {
+		t.Errorf("O's GetStateKey() = %s, want 110220000", key)
+	}
+	o.Learn(o.GetStateKey(board), 2, 1.0, "")
+	if x.GetValue("110220000") <= 0 {
+		t.Errorf("X's view did not see the value O learned")
+	}
+
+	path := filepath.Join(t.TempDir(), "ac")
+	if err := a.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded := NewActorCriticAgent(2)
+	loaded.Relative = false
+	if err := loaded.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Relative || loaded.GetValue("110220000") != a.GetValue("110220000") {
+		t.Errorf("Load() = relative %v, value %v, want the saved relative model", loaded.Relative, loaded.GetValue("110220000"))
+	}
+}
@@ -1,7 +1,9 @@
 package agent
 
 import (
+	"math/rand"
 	"strconv"
+	"time"
 
 	"github.com/jpotts18/tictactoe/game"
 )
@@ -18,17 +20,86 @@ type Agent interface {
 	GetStateKey(board *game.Board) string
 }
 
+// LearningAgent is an Agent whose learned model can be saved and restored.
+// Implementations append their own file extension to filename.
+type LearningAgent interface {
+	Agent
+
+	Save(filename string) error
+	Load(filename string) error
+}
+
+// EvaluatingAgent is an Agent that can switch off exploration, making GetMove
+// return its greedy move
+type EvaluatingAgent interface {
+	Agent
+
+	SetEvaluating(evaluating bool)
+}
+
+// SeatedAgent is an Agent whose seat can be changed between games. Every
+// agent built on BaseAgent is one.
+type SeatedAgent interface {
+	Agent
+
+	SetPlayer(player int)
+}
+
+// Seeder is an agent or explorer whose random choices come from a source
+// that Seed fixes, so a run can be repeated
+type Seeder interface {
+	Seed(seed int64)
+}
+
+// newRand returns a random source seeded from the clock
+func newRand() *rand.Rand {
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+// SelfPlayAgent is a learning agent that can take both seats of a self-play
+// game. ForSeat returns a view of the agent playing as player that shares
+// its learned values but keeps its own per-game state, such as an episode.
+// With the relative encoding a position means the same thing to whichever
+// seat is to move, so both seats learn into one table.
+type SelfPlayAgent interface {
+	Agent
+
+	ForSeat(player int) Agent
+}
+
 // BaseAgent provides common functionality for all agents
 type BaseAgent struct {
 	Player int
+
+	// Relative encodes states from Player's side, with its own marks as 1 and
+	// the opponent's as 2, instead of by absolute mark. Values learned in one
+	// seat then hold in the other. For player 1 both encodings agree.
+	Relative bool
+}
+
+// SetPlayer seats the agent as player
+func (b *BaseAgent) SetPlayer(player int) {
+	b.Player = player
 }
 
 func (b *BaseAgent) GetStateKey(board *game.Board) string {
 	state := ""
 	for i := 0; i < 9; i++ {
-		state += strconv.Itoa(board.GetCell(i))
+		cell := board.GetCell(i)
+		if b.Relative && b.Player == 2 && cell != 0 {
+			cell = 3 - cell
+		}
+		state += strconv.Itoa(cell)
 	}
 	return state
 }
 
+// ownMark is the mark that stands for the agent in its state keys
+func (b *BaseAgent) ownMark() int {
+	if b.Relative {
+		return 1
+	}
+	return b.Player
+}
+
 
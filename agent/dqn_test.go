@@ -0,0 +1,58 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+func TestDQNAgentGetMoveLegal(t *testing.T) {
+	agent := NewDQNAgent(1, 1)
+	agent.Evaluating = true
+
+	b := game.NewBoard()
+	b.SetState([][]int{
+		{1, 2, 1},
+		{2, 0, 2},
+		{1, 2, 0},
+	})
+
+	for i := 0; i < 10; i++ {
+		if move := agent.GetMove(b, 1); move != 4 && move != 8 {
+			t.Fatalf("GetMove() = %v, want one of [4 8]", move)
+		}
+	}
+}
+
+func TestDQNAgentDeterministic(t *testing.T) {
+	play := func() []float64 {
+		agent := NewDQNAgent(1, 42)
+		opponent := NewRandomAgent(2)
+		for i := 0; i < 50; i++ {
+			b := game.NewBoard()
+			for {
+				state := agent.GetStateKey(b)
+				move := agent.GetMove(b, 1)
+				b.MakeMove(move, 1)
+				if over, _ := b.IsGameOver(); over {
+					agent.Learn(state, move, 1, "")
+					break
+				}
+				b.MakeMove(opponent.GetMove(b, 2), 2)
+				if over, _ := b.IsGameOver(); over {
+					agent.Learn(state, move, -1, "")
+					break
+				}
+				agent.Learn(state, move, 0, agent.GetStateKey(b))
+			}
+		}
+		return agent.GetQValues("000000000")
+	}
+
+	first, second := play(), play()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Q-values differ between runs with the same seed: %v vs %v", first, second)
+		}
+	}
+}
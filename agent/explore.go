@@ -0,0 +1,340 @@
+package agent
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Explorer chooses the moves a tabular agent makes while training. Agents
+// play greedily instead while evaluating.
+type Explorer interface {
+	// Choose picks one of moves given the Q-values of state, indexed by cell
+	Choose(state string, moves []int, qValues []float64) int
+
+	// Observe records that action was taken in state
+	Observe(state string, action int)
+
+	// Step advances the exploration schedule. Q-learning and SARSA step once
+	// per update, Monte Carlo once per episode.
+	Step()
+
+	// Rate returns the current amount of exploration: epsilon, the Boltzmann
+	// temperature or the UCB1 constant
+	Rate() float64
+}
+
+// Exploring is implemented by agents whose exploration strategy can be
+// replaced
+type Exploring interface {
+	SetExplorer(e Explorer)
+}
+
+// initialValue returns the value unseen Q-table rows start at
+func initialValue(e Explorer) float64 {
+	if o, ok := e.(*Optimistic); ok {
+		return o.Value
+	}
+	return 0
+}
+
+// newRow returns a Q-table row for an unseen state
+func newRow(e Explorer) []float64 {
+	row := make([]float64, 9)
+	if value := initialValue(e); value != 0 {
+		for i := range row {
+			row[i] = value
+		}
+	}
+	return row
+}
+
+// greedyMove returns the move with the highest Q-value, the first on ties
+func greedyMove(moves []int, qValues []float64) int {
+	best := moves[0]
+	for _, move := range moves[1:] {
+		if qValues[move] > qValues[best] {
+			best = move
+		}
+	}
+	return best
+}
+
+// Schedule gives an exploration parameter after a number of steps
+type Schedule interface {
+	At(step int) float64
+}
+
+// ExponentialSchedule multiplies Start by Decay every step, down to Min
+type ExponentialSchedule struct {
+	Start, Min, Decay float64
+}
+
+func (s ExponentialSchedule) At(step int) float64 {
+	return math.Max(s.Min, s.Start*math.Pow(s.Decay, float64(step)))
+}
+
+// LinearSchedule moves from Start to End over Steps steps, then stays at End
+type LinearSchedule struct {
+	Start, End float64
+	Steps      int
+}
+
+func (s LinearSchedule) At(step int) float64 {
+	if step >= s.Steps {
+		return s.End
+	}
+	return s.Start + (s.End-s.Start)*float64(step)/float64(s.Steps)
+}
+
+// StepSchedule multiplies Start by Factor every Every steps, down to Min
+type StepSchedule struct {
+	Start, Min, Factor float64
+	Every              int
+}
+
+func (s StepSchedule) At(step int) float64 {
+	return math.Max(s.Min, s.Start*math.Pow(s.Factor, float64(step/s.Every)))
+}
+
+// scheduled tracks the current value of a schedule
+type scheduled struct {
+	schedule Schedule
+	steps    int
+	value    float64
+	rng      *rand.Rand
+}
+
+func newScheduled(schedule Schedule) scheduled {
+	return scheduled{schedule: schedule, value: schedule.At(0), rng: newRand()}
+}
+
+// Seed fixes the random source of the explorer
+func (s *scheduled) Seed(seed int64) {
+	s.rng.Seed(seed)
+}
+
+func (s *scheduled) Observe(state string, action int) {}
+
+func (s *scheduled) Step() {
+	s.steps++
+	s.value = s.schedule.At(s.steps)
+}
+
+func (s *scheduled) Rate() float64 {
+	return s.value
+}
+
+// EpsilonGreedy plays a random move with probability epsilon and the best
+// move otherwise
+type EpsilonGreedy struct {
+	scheduled
+}
+
+func NewEpsilonGreedy(epsilon Schedule) *EpsilonGreedy {
+	return &EpsilonGreedy{newScheduled(epsilon)}
+}
+
+func (e *EpsilonGreedy) Choose(state string, moves []int, qValues []float64) int {
+	if e.rng.Float64() < e.value {
+		return moves[e.rng.Intn(len(moves))]
+	}
+	return greedyMove(moves, qValues)
+}
+
+// Boltzmann plays each move with probability proportional to
+// exp(Q / temperature). High temperatures explore uniformly; as the
+// temperature falls towards zero it becomes greedy.
+type Boltzmann struct {
+	scheduled
+}
+
+func NewBoltzmann(temperature Schedule) *Boltzmann {
+	return &Boltzmann{newScheduled(temperature)}
+}
+
+func (b *Boltzmann) Choose(state string, moves []int, qValues []float64) int {
+	if b.value <= 0 {
+		return greedyMove(moves, qValues)
+	}
+	// Subtracting the largest Q-value keeps exp from overflowing
+	highest := qValues[greedyMove(moves, qValues)]
+	weights := make([]float64, len(moves))
+	total := 0.0
+	for i, move := range moves {
+		weights[i] = math.Exp((qValues[move] - highest) / b.value)
+		total += weights[i]
+	}
+	r := b.rng.Float64() * total
+	for i, weight := range weights {
+		if r < weight {
+			return moves[i]
+		}
+		r -= weight
+	}
+	return moves[len(moves)-1]
+}
+
+// UCB1 plays every move of a state once, then the move maximizing
+// Q + C*sqrt(ln N / n), where N counts visits to the state and n plays of the
+// move. Rarely tried moves get a bonus that shrinks as they are tried.
+type UCB1 struct {
+	C      float64
+	counts map[string][]int
+	rng    *rand.Rand
+}
+
+func NewUCB1(c float64) *UCB1 {
+	return &UCB1{C: c, counts: make(map[string][]int), rng: newRand()}
+}
+
+// Seed fixes the random source of the explorer
+func (u *UCB1) Seed(seed int64) {
+	u.rng.Seed(seed)
+}
+
+func (u *UCB1) Choose(state string, moves []int, qValues []float64) int {
+	counts, exists := u.counts[state]
+	if !exists {
+		return moves[u.rng.Intn(len(moves))]
+	}
+	total := 0
+	var untried []int
+	for _, move := range moves {
+		total += counts[move]
+		if counts[move] == 0 {
+			untried = append(untried, move)
+		}
+	}
+	if len(untried) > 0 {
+		return untried[u.rng.Intn(len(untried))]
+	}
+
+	best, bestScore := moves[0], math.Inf(-1)
+	for _, move := range moves {
+		score := qValues[move] + u.C*math.Sqrt(math.Log(float64(total))/float64(counts[move]))
+		if score > bestScore {
+			best, bestScore = move, score
+		}
+	}
+	return best
+}
+
+func (u *UCB1) Observe(state string, action int) {
+	counts, exists := u.counts[state]
+	if !exists {
+		counts = make([]int, 9)
+		u.counts[state] = counts
+	}
+	counts[action]++
+}
+
+func (u *UCB1) Step() {}
+
+func (u *UCB1) Rate() float64 {
+	return u.C
+}
+
+// Greedy always plays the best move
+type Greedy struct{}
+
+func (Greedy) Choose(state string, moves []int, qValues []float64) int {
+	return greedyMove(moves, qValues)
+}
+
+func (Greedy) Observe(state string, action int) {}
+func (Greedy) Step()                            {}
+func (Greedy) Rate() float64                    { return 0 }
+
+// Optimistic starts unseen Q-values at Value instead of 0 and chooses moves
+// with Explorer, greedily by default. With Value above any achievable return,
+// untried moves look better than tried ones until they have been tried, so
+// even a greedy agent explores. Monte Carlo replaces a Q-value with the
+// average return on its first update, so the optimism lasts one visit.
+type Optimistic struct {
+	Value float64
+	Explorer
+}
+
+func NewOptimistic(value float64, e Explorer) *Optimistic {
+	if e == nil {
+		e = Greedy{}
+	}
+	return &Optimistic{Value: value, Explorer: e}
+}
+
+// Seed fixes the random source of the wrapped explorer
+func (o *Optimistic) Seed(seed int64) {
+	if seeder, ok := o.Explorer.(Seeder); ok {
+		seeder.Seed(seed)
+	}
+}
+
+// defaultExplorer is the epsilon-greedy schedule described by opts
+func defaultExplorer(opts Options) Explorer {
+	return NewEpsilonGreedy(ExponentialSchedule{Start: opts.Epsilon, Min: opts.MinEpsilon, Decay: opts.Decay})
+}
+
+// ParseExplorer builds an explorer from a spec of the form
+// name[:key=value,...]. The names are
+//
+//	epsilon     epsilon-greedy, keys start, min and schedule
+//	boltzmann   softmax over Q-values, the same keys for the temperature
+//	ucb         UCB1, key c (default sqrt 2)
+//	optimistic  greedy with optimistic initial values, key value (default 1)
+//
+// schedule is exponential (key decay), linear (key steps, reaching min after
+// that many steps) or step (keys factor and every). An empty spec is the
+// default epsilon-greedy schedule. Every call returns a new explorer, since
+// explorers keep per-agent state.
+func ParseExplorer(spec string) (Explorer, error) {
+	name, params, err := parseSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var e Explorer
+	switch name {
+	case "", "epsilon":
+		opts := DefaultOptions()
+		e = NewEpsilonGreedy(params.schedule(opts.Epsilon, opts.MinEpsilon, opts.Decay))
+	case "boltzmann":
+		e = NewBoltzmann(params.schedule(1, 0.05, 0.9999))
+	case "ucb":
+		e = NewUCB1(params.Float("c", math.Sqrt2))
+	case "optimistic":
+		e = NewOptimistic(params.Float("value", 1), nil)
+	default:
+		return nil, fmt.Errorf("unknown explorer %q", name)
+	}
+	if err := params.finish(); err != nil {
+		return nil, fmt.Errorf("explorer %q: %v", spec, err)
+	}
+	return e, nil
+}
+
+// schedule reads the schedule keys, falling back to an exponential schedule
+// with the given start, floor and decay
+func (p *Params) schedule(start, min, decay float64) Schedule {
+	start = p.Float("start", start)
+	min = p.Float("min", min)
+	switch kind := p.Get("schedule", "exponential"); kind {
+	case "exponential":
+		return ExponentialSchedule{Start: start, Min: min, Decay: p.Float("decay", decay)}
+	case "linear":
+		if steps := p.Int("steps", 100000); steps > 0 {
+			return LinearSchedule{Start: start, End: min, Steps: steps}
+		}
+	case "step":
+		factor := p.Float("factor", 0.5)
+		if every := p.Int("every", 20000); every > 0 {
+			return StepSchedule{Start: start, Min: min, Factor: factor, Every: every}
+		}
+	default:
+		if p.err == nil {
+			p.err = fmt.Errorf("unknown schedule %q", kind)
+		}
+	}
+	// The spec is rejected; this stand-in is never stepped
+	return ExponentialSchedule{Start: start, Min: min, Decay: decay}
+}
@@ -0,0 +1,143 @@
+package agent
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSchedules(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+		step     int
+		want     float64
+	}{
+		{"exponential start", ExponentialSchedule{Start: 0.8, Min: 0.1, Decay: 0.5}, 0, 0.8},
+		{"exponential decays", ExponentialSchedule{Start: 0.8, Min: 0.1, Decay: 0.5}, 2, 0.2},
+		{"exponential floor", ExponentialSchedule{Start: 0.8, Min: 0.1, Decay: 0.5}, 10, 0.1},
+		{"linear start", LinearSchedule{Start: 1, End: 0.2, Steps: 8}, 0, 1},
+		{"linear halfway", LinearSchedule{Start: 1, End: 0.2, Steps: 8}, 4, 0.6},
+		{"linear end", LinearSchedule{Start: 1, End: 0.2, Steps: 8}, 100, 0.2},
+		{"step before first drop", StepSchedule{Start: 0.8, Min: 0.1, Factor: 0.5, Every: 10}, 9, 0.8},
+		{"step after two drops", StepSchedule{Start: 0.8, Min: 0.1, Factor: 0.5, Every: 10}, 25, 0.2},
+		{"step floor", StepSchedule{Start: 0.8, Min: 0.1, Factor: 0.5, Every: 10}, 100, 0.1},
+	}
+	for _, tt := range tests {
+		if got := tt.schedule.At(tt.step); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: At(%d) = %v, want %v", tt.name, tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestParseExplorer(t *testing.T) {
+	tests := []struct {
+		spec     string
+		wantRate float64
+		wantErr  bool
+	}{
+		{"", 0.9, false},
+		{"epsilon:start=0.5,min=0.05,schedule=linear,steps=1000", 0.5, false},
+		{"epsilon:schedule=step,factor=0.5,every=100", 0.9, false},
+		{"boltzmann:start=2", 2, false},
+		{"ucb:c=0.5", 0.5, false},
+		{"optimistic:value=2", 0, false},
+		{"softmax", 0, true},
+		{"epsilon:start", 0, true},
+		{"epsilon:start=high", 0, true},
+		{"epsilon:schedule=cosine", 0, true},
+		{"epsilon:schedule=linear,steps=0", 0, true},
+		{"epsilon:schedule=step,every=0", 0, true},
+		{"boltzmann:schedule=step,every=-5", 0, true},
+		{"ucb:temperature=1", 0, true},
+	}
+	for _, tt := range tests {
+		e, err := ParseExplorer(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseExplorer(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if err == nil && e.Rate() != tt.wantRate {
+			t.Errorf("ParseExplorer(%q).Rate() = %v, want %v", tt.spec, e.Rate(), tt.wantRate)
+		}
+	}
+}
+
+func TestBoltzmannTemperature(t *testing.T) {
+	moves := []int{0, 4, 8}
+	qValues := make([]float64, 9)
+	qValues[4] = 1
+
+	cold := NewBoltzmann(ExponentialSchedule{Start: 0.01, Min: 0.01, Decay: 1})
+	for i := 0; i < 100; i++ {
+		if move := cold.Choose("", moves, qValues); move != 4 {
+			t.Fatalf("Choose() at a low temperature = %v, want the best move 4", move)
+		}
+	}
+
+	hot := NewBoltzmann(ExponentialSchedule{Start: 100, Min: 100, Decay: 1})
+	counts := make(map[int]int)
+	for i := 0; i < 3000; i++ {
+		counts[hot.Choose("", moves, qValues)]++
+	}
+	for _, move := range moves {
+		if counts[move] < 800 {
+			t.Errorf("move %d chosen %d of 3000 times at a high temperature, want about 1000", move, counts[move])
+		}
+	}
+}
+
+func TestUCB1TriesEveryMove(t *testing.T) {
+	u := NewUCB1(math.Sqrt2)
+	moves := []int{1, 2, 3}
+	qValues := make([]float64, 9)
+	qValues[2] = 1
+
+	seen := make(map[int]bool)
+	for i := 0; i < len(moves); i++ {
+		move := u.Choose("s", moves, qValues)
+		if seen[move] {
+			t.Fatalf("Choose() repeated move %d before trying them all", move)
+		}
+		seen[move] = true
+		u.Observe("s", move)
+	}
+
+	// A clearly better move is played even though the others' bonuses grow
+	for i := 0; i < 100; i++ {
+		u.Observe("s", 2)
+	}
+	qValues[2] = 5
+	if move := u.Choose("s", moves, qValues); move != 2 {
+		t.Errorf("Choose() = %v, want the best move 2", move)
+	}
+	// A move with a much smaller count eventually gets its bonus
+	qValues[2] = 0.1
+	if move := u.Choose("s", moves, qValues); move == 2 {
+		t.Errorf("Choose() = 2, want a rarely tried move once values are close")
+	}
+}
+
+func TestOptimisticInitialValues(t *testing.T) {
+	agents := map[string]interface {
+		tabularAgent
+		Exploring
+	}{
+		"qlearning":  NewQAgent(1),
+		"sarsa":      NewSarsaAgent(1),
+		"montecarlo": NewMonteCarloAgent(1),
+	}
+	for name, a := range agents {
+		a.SetExplorer(NewOptimistic(2, nil))
+		if got := a.GetQValues("000000000")[4]; got != 2 {
+			t.Errorf("%s: unseen Q-value = %v, want the optimistic 2", name, got)
+		}
+		a.Learn("000000000", 4, 0, "")
+		a.Learn("000000000", 4, 0, "")
+		if got := a.GetQValues("000000000")[4]; got >= 2 {
+			t.Errorf("%s: Q-value after unrewarded updates = %v, want below 2", name, got)
+		}
+		if got := a.GetQValues("000000000")[0]; got != 2 {
+			t.Errorf("%s: untried Q-value = %v, want 2", name, got)
+		}
+	}
+}
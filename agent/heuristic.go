@@ -0,0 +1,164 @@
+package agent
+
+import "github.com/jpotts18/tictactoe/game"
+
+// Rule is one of Newell and Simon's tic-tac-toe strategy rules
+type Rule int
+
+const (
+	RuleNone Rule = iota
+	RuleWin
+	RuleBlock
+	RuleFork
+	RuleBlockFork
+	RuleCenter
+	RuleOppositeCorner
+	RuleEmptyCorner
+	RuleEmptySide
+)
+
+func (r Rule) String() string {
+	switch r {
+	case RuleWin:
+		return "win"
+	case RuleBlock:
+		return "block"
+	case RuleFork:
+		return "fork"
+	case RuleBlockFork:
+		return "block fork"
+	case RuleCenter:
+		return "center"
+	case RuleOppositeCorner:
+		return "opposite corner"
+	case RuleEmptyCorner:
+		return "empty corner"
+	case RuleEmptySide:
+		return "empty side"
+	default:
+		return "none"
+	}
+}
+
+var corners = []int{0, 2, 6, 8}
+var sides = []int{1, 3, 5, 7}
+
+// HeuristicAgent plays the Newell and Simon priority rules in order: win,
+// block, fork, block a fork, center, opposite corner, empty corner, empty side.
+// It is deterministic and records the rule behind its last move in LastRule.
+type HeuristicAgent struct {
+	BaseAgent
+	LastRule Rule
+}
+
+func NewHeuristicAgent(player int) *HeuristicAgent {
+	return &HeuristicAgent{
+		BaseAgent: BaseAgent{Player: player},
+	}
+}
+
+func (h *HeuristicAgent) GetMove(board *game.Board, player int) int {
+	move, rule := h.ChooseMove(board, player)
+	h.LastRule = rule
+	return move
+}
+
+// ChooseMove returns the move for player together with the rule that selected it
+func (h *HeuristicAgent) ChooseMove(board *game.Board, player int) (int, Rule) {
+	moves := board.GetAvailableMoves()
+	if len(moves) == 0 {
+		return -1, RuleNone
+	}
+	opponent := player%2 + 1
+
+	if wins := winningMoves(board, player); len(wins) > 0 {
+		return wins[0], RuleWin
+	}
+	if blocks := winningMoves(board, opponent); len(blocks) > 0 {
+		return blocks[0], RuleBlock
+	}
+	for _, move := range moves {
+		if isFork(board, move, player) {
+			return move, RuleFork
+		}
+	}
+	if move, ok := blockFork(board, player); ok {
+		return move, RuleBlockFork
+	}
+	if board.IsEmpty(4) {
+		return 4, RuleCenter
+	}
+	for _, corner := range corners {
+		if board.GetCell(corner) == opponent && board.IsEmpty(8-corner) {
+			return 8 - corner, RuleOppositeCorner
+		}
+	}
+	for _, corner := range corners {
+		if board.IsEmpty(corner) {
+			return corner, RuleEmptyCorner
+		}
+	}
+	for _, side := range sides {
+		if board.IsEmpty(side) {
+			return side, RuleEmptySide
+		}
+	}
+	return moves[0], RuleNone
+}
+
+// Learn is a no-op for HeuristicAgent as it doesn't learn
+func (h *HeuristicAgent) Learn(oldState string, action int, reward float64, newState string) {
+}
+
+// winningMoves returns the moves that would immediately win the game for player
+func winningMoves(board *game.Board, player int) []int {
+	var wins []int
+	for _, move := range board.GetAvailableMoves() {
+		boardCopy := *board
+		boardCopy.MakeMove(move, player)
+		if gameOver, winner := boardCopy.IsGameOver(); gameOver && winner == player {
+			wins = append(wins, move)
+		}
+	}
+	return wins
+}
+
+// isFork reports whether playing move leaves player with two ways to win
+func isFork(board *game.Board, move int, player int) bool {
+	boardCopy := *board
+	boardCopy.MakeMove(move, player)
+	return len(winningMoves(&boardCopy, player)) >= 2
+}
+
+// blockFork finds a move that stops the opponent from forking. It prefers
+// making a threat whose forced reply doesn't give the opponent a fork, and
+// otherwise occupies the opponent's fork square.
+func blockFork(board *game.Board, player int) (int, bool) {
+	opponent := player%2 + 1
+	var forks []int
+	for _, move := range board.GetAvailableMoves() {
+		if isFork(board, move, opponent) {
+			forks = append(forks, move)
+		}
+	}
+	if len(forks) == 0 {
+		return -1, false
+	}
+	if len(forks) == 1 {
+		return forks[0], true
+	}
+
+	for _, move := range board.GetAvailableMoves() {
+		boardCopy := *board
+		boardCopy.MakeMove(move, player)
+		threats := winningMoves(&boardCopy, player)
+		if len(threats) != 1 {
+			continue
+		}
+		// The opponent must block; make sure blocking doesn't hand them a fork
+		if !isFork(&boardCopy, threats[0], opponent) {
+			return move, true
+		}
+	}
+	return forks[0], true
+}
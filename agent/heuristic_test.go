@@ -0,0 +1,137 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+func TestHeuristicChooseMove(t *testing.T) {
+	tests := []struct {
+		name     string
+		board    [][]int
+		player   int
+		wantMove int
+		wantRule Rule
+	}{
+		{
+			name: "empty board takes center",
+			board: [][]int{
+				{0, 0, 0},
+				{0, 0, 0},
+				{0, 0, 0},
+			},
+			player:   1,
+			wantMove: 4,
+			wantRule: RuleCenter,
+		},
+		{
+			name: "win beats block",
+			board: [][]int{
+				{1, 1, 0},
+				{2, 2, 0},
+				{0, 0, 0},
+			},
+			player:   2,
+			wantMove: 5,
+			wantRule: RuleWin,
+		},
+		{
+			name: "block opponent row",
+			board: [][]int{
+				{1, 1, 0},
+				{0, 2, 0},
+				{0, 0, 0},
+			},
+			player:   2,
+			wantMove: 2,
+			wantRule: RuleBlock,
+		},
+		{
+			name: "create fork",
+			board: [][]int{
+				{2, 0, 0},
+				{0, 1, 2},
+				{0, 0, 1},
+			},
+			player:   1,
+			wantMove: 6,
+			wantRule: RuleFork,
+		},
+		{
+			name: "block fork with a forcing side move",
+			board: [][]int{
+				{1, 0, 0},
+				{0, 2, 0},
+				{0, 0, 1},
+			},
+			player:   2,
+			wantMove: 1,
+			wantRule: RuleBlockFork,
+		},
+		{
+			name: "opposite corner",
+			board: [][]int{
+				{2, 0, 0},
+				{0, 1, 0},
+				{0, 0, 0},
+			},
+			player:   1,
+			wantMove: 8,
+			wantRule: RuleOppositeCorner,
+		},
+		{
+			name: "empty corner after center opening",
+			board: [][]int{
+				{0, 0, 0},
+				{0, 1, 0},
+				{0, 0, 0},
+			},
+			player:   2,
+			wantMove: 0,
+			wantRule: RuleEmptyCorner,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := NewHeuristicAgent(tt.player)
+			b := game.NewBoard()
+			b.SetState(tt.board)
+
+			move, rule := agent.ChooseMove(b, tt.player)
+			if move != tt.wantMove || rule != tt.wantRule {
+				t.Errorf("ChooseMove() = (%v, %v), want (%v, %v)", move, rule, tt.wantMove, tt.wantRule)
+			}
+		})
+	}
+}
+
+// TestHeuristicNeverLoses plays the heuristic agent against every possible
+// sequence of opponent moves from both seats
+func TestHeuristicNeverLoses(t *testing.T) {
+	var explore func(b *game.Board, toMove, heuristicPlayer int)
+	explore = func(b *game.Board, toMove, heuristicPlayer int) {
+		if gameOver, winner := b.IsGameOver(); gameOver {
+			if winner != 0 && winner != heuristicPlayer {
+				t.Fatalf("heuristic agent lost as player %d:\n%s", heuristicPlayer, b.String())
+			}
+			return
+		}
+		if toMove == heuristicPlayer {
+			boardCopy := *b
+			move, _ := NewHeuristicAgent(heuristicPlayer).ChooseMove(&boardCopy, heuristicPlayer)
+			boardCopy.MakeMove(move, heuristicPlayer)
+			explore(&boardCopy, toMove%2+1, heuristicPlayer)
+			return
+		}
+		for _, move := range b.GetAvailableMoves() {
+			boardCopy := *b
+			boardCopy.MakeMove(move, toMove)
+			explore(&boardCopy, toMove%2+1, heuristicPlayer)
+		}
+	}
+
+	explore(game.NewBoard(), 1, 1)
+	explore(game.NewBoard(), 1, 2)
+}
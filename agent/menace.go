@@ -0,0 +1,231 @@
+package agent
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+// BeadRules controls how MENACE fills and reinforces its matchboxes
+type BeadRules struct {
+	// Initial is the number of beads per legal move in a new box, indexed by
+	// how many moves MENACE has already made in the game
+	Initial []int `json:"initial"`
+	Win     int   `json:"win"`
+	Draw    int   `json:"draw"`
+	Loss    int   `json:"loss"`
+}
+
+// DefaultBeadRules are Michie's original rules: 4, 3, 2 and 1 beads for
+// MENACE's first four moves, +3 for a win, +1 for a draw and -1 for a loss
+func DefaultBeadRules() BeadRules {
+	return BeadRules{Initial: []int{4, 3, 2, 1}, Win: 3, Draw: 1, Loss: -1}
+}
+
+type menaceMove struct {
+	box  string
+	bead int
+}
+
+// MenaceAgent is a Matchbox Educable Noughts And Crosses Engine. It keeps one
+// matchbox per canonical position holding beads for each legal move, draws
+// moves in proportion to bead counts, and adds or removes beads at the end of
+// each game.
+type MenaceAgent struct {
+	BaseAgent
+	boxes   map[string][]int
+	rules   BeadRules
+	episode []menaceMove
+	rng     *rand.Rand
+}
+
+func NewMenaceAgent(player int, rules BeadRules) *MenaceAgent {
+	return &MenaceAgent{
+		BaseAgent: BaseAgent{Player: player, Relative: true},
+		boxes:     make(map[string][]int),
+		rules:     rules,
+		episode:   make([]menaceMove, 0),
+		rng:       newRand(),
+	}
+}
+
+// Seed fixes the random source the beads are drawn from
+func (m *MenaceAgent) Seed(seed int64) {
+	m.rng.Seed(seed)
+}
+
+// matchbox returns the bead counts for a canonical state, filling a new or
+// emptied box with the initial beads for its stage. As in the original
+// MENACE, moves that are symmetric in the position share a single bead slot.
+func (m *MenaceAgent) matchbox(canonical string) []int {
+	beads, exists := m.boxes[canonical]
+	if exists && total(beads) > 0 {
+		return beads
+	}
+
+	marks := 0
+	for i := 0; i < 9; i++ {
+		if canonical[i] != '0' {
+			marks++
+		}
+	}
+	stage := marks / 2
+	initial := 1
+	if stage < len(m.rules.Initial) {
+		initial = m.rules.Initial[stage]
+	} else if len(m.rules.Initial) > 0 {
+		initial = m.rules.Initial[len(m.rules.Initial)-1]
+	}
+
+	beads = make([]int, 9)
+	for i := 0; i < 9; i++ {
+		if canonical[i] == '0' && orbitRepresentative(canonical, i) == i {
+			beads[i] = initial
+		}
+	}
+	m.boxes[canonical] = beads
+	return beads
+}
+
+// orbitRepresentative returns the lowest cell among those the position's own
+// symmetries map pos to
+func orbitRepresentative(state string, pos int) int {
+	rep := pos
+	for t := 1; t < 8; t++ {
+		if game.Transform(state, t) == state && game.FromCanonical(pos, t) < rep {
+			rep = game.FromCanonical(pos, t)
+		}
+	}
+	return rep
+}
+
+func total(beads []int) int {
+	sum := 0
+	for _, count := range beads {
+		sum += count
+	}
+	return sum
+}
+
+func (m *MenaceAgent) GetMove(board *game.Board, player int) int {
+	moves := board.GetAvailableMoves()
+	if len(moves) == 0 {
+		return -1
+	}
+
+	canonical, t := game.Canonical(m.GetStateKey(board))
+	beads := m.matchbox(canonical)
+
+	draw := m.rng.Intn(total(beads))
+	for pos, count := range beads {
+		draw -= count
+		if draw < 0 {
+			return game.FromCanonical(pos, t)
+		}
+	}
+	return moves[0]
+}
+
+func (m *MenaceAgent) Learn(state string, action int, reward float64, newState string) {
+	canonical, t := game.Canonical(state)
+	bead := orbitRepresentative(canonical, game.ToCanonical(action, t))
+	m.episode = append(m.episode, menaceMove{canonical, bead})
+
+	if newState == "" {
+		m.reinforce(reward)
+		return
+	}
+
+	gameOver, winner := game.FromStateString(newState).IsGameOver()
+	if !gameOver {
+		return
+	}
+	switch winner {
+	case m.ownMark():
+		m.reinforce(1)
+	case 0:
+		m.reinforce(0)
+	default:
+		m.reinforce(-1)
+	}
+}
+
+// ForSeat returns a view of the agent playing as player that shares its
+// matchboxes but records its own episode
+func (m *MenaceAgent) ForSeat(player int) Agent {
+	seat := *m
+	seat.Player = player
+	seat.episode = make([]menaceMove, 0)
+	return &seat
+}
+
+// reinforce applies the bead rules for the outcome's sign to every move of
+// the finished game
+func (m *MenaceAgent) reinforce(outcome float64) {
+	delta := m.rules.Draw
+	if outcome > 0 {
+		delta = m.rules.Win
+	} else if outcome < 0 {
+		delta = m.rules.Loss
+	}
+
+	for _, move := range m.episode {
+		beads := m.matchbox(move.box)
+		beads[move.bead] += delta
+		if beads[move.bead] < 0 {
+			beads[move.bead] = 0
+		}
+	}
+	m.episode = make([]menaceMove, 0)
+}
+
+// Matchboxes returns the bead counts of every box, keyed by canonical state
+func (m *MenaceAgent) Matchboxes() map[string][]int {
+	return m.boxes
+}
+
+// Report writes every matchbox as a grid of bead counts, with marks shown as X and O
+func (m *MenaceAgent) Report(w io.Writer) {
+	states := make([]string, 0, len(m.boxes))
+	for state := range m.boxes {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+
+	for _, state := range states {
+		beads := m.boxes[state]
+		fmt.Fprintf(w, "Box %s (%d beads)\n", state, total(beads))
+		for row := 0; row < 3; row++ {
+			for col := 0; col < 3; col++ {
+				pos := row*3 + col
+				switch state[pos] {
+				case '1':
+					fmt.Fprintf(w, "%4s", "X")
+				case '2':
+					fmt.Fprintf(w, "%4s", "O")
+				default:
+					fmt.Fprintf(w, "%4d", beads[pos])
+				}
+			}
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func (m *MenaceAgent) Save(filename string) error {
+	return SaveMenace(filename+".menace", m.boxes, m.rules)
+}
+
+func (m *MenaceAgent) Load(filename string) error {
+	boxes, rules, err := LoadMenace(filename + ".menace")
+	if err != nil {
+		return err
+	}
+	m.boxes = boxes
+	m.rules = rules
+	return nil
+}
@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+func TestMenaceMatchbox(t *testing.T) {
+	tests := []struct {
+		name  string
+		state string
+		want  []int
+	}{
+		{
+			name:  "empty board has corner, edge and center beads",
+			state: "000000000",
+			want:  []int{4, 4, 0, 0, 4, 0, 0, 0, 0},
+		},
+		{
+			name:  "second move after center opening",
+			state: "000010000",
+			want:  []int{4, 4, 0, 0, 0, 0, 0, 0, 0},
+		},
+		{
+			name:  "asymmetric position keeps every empty cell",
+			state: "000010021",
+			want:  []int{3, 3, 3, 3, 0, 3, 3, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := NewMenaceAgent(1, DefaultBeadRules())
+			canonical, _ := game.Canonical(tt.state)
+			if canonical != tt.state {
+				t.Fatalf("test state %v is not canonical (%v)", tt.state, canonical)
+			}
+			got := agent.matchbox(tt.state)
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("matchbox(%v) = %v, want %v", tt.state, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestMenaceReinforce(t *testing.T) {
+	tests := []struct {
+		name     string
+		newState string
+		reward   float64
+		want     int
+	}{
+		{name: "win adds beads", newState: "111220000", want: 7},
+		{name: "draw adds a bead", newState: "", reward: 0, want: 5},
+		{name: "loss removes a bead", newState: "", reward: -1, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := NewMenaceAgent(1, DefaultBeadRules())
+			agent.Learn("000000000", 8, tt.reward, tt.newState)
+
+			// Cell 8 is a corner, which the empty-board box stores at cell 0
+			if got := agent.Matchboxes()["000000000"][0]; got != tt.want {
+				t.Errorf("corner beads after game = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMenaceSaveLoad(t *testing.T) {
+	agent := NewMenaceAgent(1, DefaultBeadRules())
+	agent.Learn("000000000", 4, 0, "111220000")
+	filename := filepath.Join(t.TempDir(), "menace")
+
+	if err := agent.Save(filename); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded := NewMenaceAgent(1, BeadRules{})
+	if err := loaded.Load(filename); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := loaded.Matchboxes()["000000000"][4]; got != 7 {
+		t.Errorf("loaded center beads = %v, want 7", got)
+	}
+	if loaded.rules.Win != 3 {
+		t.Errorf("loaded rules.Win = %v, want 3", loaded.rules.Win)
+	}
+}
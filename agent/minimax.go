@@ -5,11 +5,22 @@ import "github.com/jpotts18/tictactoe/game"
 // MinimaxAgent implements a perfect player using the minimax algorithm
 type MinimaxAgent struct {
 	BaseAgent
+	Depth int // moves searched after the agent's own; positions beyond count as draws
 }
 
+// DefaultMinimaxDepth searches deep enough to never lose
+const DefaultMinimaxDepth = 5
+
 func NewMinimaxAgent(player int) *MinimaxAgent {
+	return NewMinimaxAgentWithDepth(player, DefaultMinimaxDepth)
+}
+
+// NewMinimaxAgentWithDepth returns a minimax agent that only looks depth moves
+// past its own, so small depths make weaker players
+func NewMinimaxAgentWithDepth(player, depth int) *MinimaxAgent {
 	return &MinimaxAgent{
 		BaseAgent: BaseAgent{Player: player},
+		Depth:     depth,
 	}
 }
 
@@ -28,7 +39,7 @@ func (m *MinimaxAgent) ChooseAction(board *game.Board) int {
 		boardCopy := *board
 		boardCopy.MakeMove(move, m.Player)
 		// Evaluate position assuming opponent plays optimally
-		score := m.minimax(&boardCopy, false, m.Player%2+1, 5) // depth of 5 moves ahead
+		score := m.minimax(&boardCopy, false, m.Player%2+1, m.Depth)
 		if score > bestScore {
 			bestScore = score
 			bestMove = move
@@ -91,7 +102,7 @@ func (m *MinimaxAgent) GetMove(board *game.Board, player int) int {
 	for _, move := range board.GetEmptyCells() {
 		boardCopy := *board
 		boardCopy.MakeMove(move, player)
-		score := m.minimax(&boardCopy, false, player%2+1, 5)
+		score := m.minimax(&boardCopy, false, player%2+1, m.Depth)
 		
 		if score > bestScore {
 			bestScore = score
@@ -0,0 +1,62 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/jpotts18/tictactoe/game"
+	"github.com/jpotts18/tictactoe/solver"
+)
+
+// TestMinimaxNeverLoses plays the minimax agent against every possible
+// sequence of opponent moves from both seats and with either side moving
+// first, and checks each of its moves against the tablebase
+func TestMinimaxNeverLoses(t *testing.T) {
+	tb := solver.Default()
+	var explore func(b *game.Board, toMove, minimaxPlayer int)
+	explore = func(b *game.Board, toMove, minimaxPlayer int) {
+		if gameOver, winner := b.IsGameOver(); gameOver {
+			if winner != 0 && winner != minimaxPlayer {
+				t.Fatalf("minimax agent lost as player %d:\n%s", minimaxPlayer, b.String())
+			}
+			return
+		}
+		if toMove == minimaxPlayer {
+			boardCopy := *b
+			move := NewMinimaxAgent(minimaxPlayer).GetMove(&boardCopy, minimaxPlayer)
+			if !tb.IsOptimal(b, minimaxPlayer, move) {
+				t.Fatalf("minimax agent played %d, which gives up the game value:\n%s", move, b.String())
+			}
+			boardCopy.MakeMove(move, minimaxPlayer)
+			explore(&boardCopy, toMove%2+1, minimaxPlayer)
+			return
+		}
+		for _, move := range b.GetAvailableMoves() {
+			boardCopy := *b
+			boardCopy.MakeMove(move, toMove)
+			explore(&boardCopy, toMove%2+1, minimaxPlayer)
+		}
+	}
+
+	for _, first := range []int{1, 2} {
+		explore(game.NewBoard(), first, 1)
+		explore(game.NewBoard(), first, 2)
+	}
+}
+
+// TestMinimaxDepth checks that a shallow search misses a fork two moves away
+// that the default depth avoids. With X on opposite corners and O in the
+// centre, O must take an edge: a corner lets X block and fork.
+func TestMinimaxDepth(t *testing.T) {
+	tb := solver.Default()
+	board := game.NewBoard()
+	board.MakeMove(2, 1)
+	board.MakeMove(4, 2)
+	board.MakeMove(6, 1)
+
+	if move := NewMinimaxAgent(2).GetMove(board, 2); !tb.IsOptimal(board, 2, move) {
+		t.Errorf("default depth played %d, which loses", move)
+	}
+	if move := NewMinimaxAgentWithDepth(2, 1).GetMove(board, 2); tb.IsOptimal(board, 2, move) {
+		t.Errorf("depth 1 played %d, want a corner that walks into the fork", move)
+	}
+}
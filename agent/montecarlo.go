@@ -1,9 +1,6 @@
 package agent
 
 import (
-	"math"
-	"math/rand"
-
 	"github.com/jpotts18/tictactoe/game"
 )
 
@@ -17,30 +14,77 @@ type MonteCarloAgent struct {
 	BaseAgent
 	qTable     map[string][]float64
 	returns    map[string]map[int][]float64
-	epsilon    float64
+	explorer   Explorer
 	gamma      float64
 	episode    []Episode
 	Evaluating bool
+	*tdStats
 }
 
 
 func NewMonteCarloAgent(player int) *MonteCarloAgent {
+	return NewMonteCarloAgentWithOptions(player, DefaultOptions())
+}
+
+// NewMonteCarloAgentWithOptions creates a Monte Carlo agent with the given
+// hyperparameters. Alpha is not used.
+func NewMonteCarloAgentWithOptions(player int, opts Options) *MonteCarloAgent {
 	return &MonteCarloAgent{
-		BaseAgent:  BaseAgent{Player: player},
+		BaseAgent:  BaseAgent{Player: player, Relative: opts.Relative},
 		qTable:     make(map[string][]float64),
 		returns:    make(map[string]map[int][]float64),
-		epsilon:    0.9,
-		gamma:      0.99,
+		explorer:   defaultExplorer(opts),
+		gamma:      opts.Gamma,
 		episode:    make([]Episode, 0),
 		Evaluating: false,
+		tdStats:    &tdStats{},
 	}
 }
 
+// GetQValues returns the Q-values for state. Unseen states get a fresh row
+// of initial values that is not stored, so evaluating a policy does not grow
+// the table.
 func (m *MonteCarloAgent) GetQValues(state string) []float64 {
-	if _, exists := m.qTable[state]; !exists {
-		m.qTable[state] = make([]float64, 9)
+	if qValues, exists := m.qTable[state]; exists {
+		return qValues
 	}
-	return m.qTable[state]
+	return newRow(m.explorer)
+}
+
+// ForSeat returns a view of the agent playing as player that shares its
+// Q-table, returns, explorer and statistics but records its own episode
+func (m *MonteCarloAgent) ForSeat(player int) Agent {
+	seat := *m
+	seat.Player = player
+	seat.episode = make([]Episode, 0)
+	return &seat
+}
+
+// Seed fixes the random source of the agent's explorer
+func (m *MonteCarloAgent) Seed(seed int64) {
+	if seeder, ok := m.explorer.(Seeder); ok {
+		seeder.Seed(seed)
+	}
+}
+
+// SetExplorer replaces the exploration strategy
+func (m *MonteCarloAgent) SetExplorer(e Explorer) {
+	m.explorer = e
+}
+
+// Epsilon returns the current exploration rate
+func (m *MonteCarloAgent) Epsilon() float64 {
+	return m.explorer.Rate()
+}
+
+// TableSize returns the number of states in the Q-table
+func (m *MonteCarloAgent) TableSize() int {
+	return len(m.qTable)
+}
+
+// SetEvaluating turns exploration off (true) or back on (false)
+func (m *MonteCarloAgent) SetEvaluating(evaluating bool) {
+	m.Evaluating = evaluating
 }
 
 func (m *MonteCarloAgent) GetMove(board *game.Board, player int) int {
@@ -51,8 +95,8 @@ func (m *MonteCarloAgent) GetMove(board *game.Board, player int) int {
 		return -1
 	}
 
-	if rand.Float64() < m.epsilon {
-		return moves[rand.Intn(len(moves))]
+	if !m.Evaluating {
+		return m.explorer.Choose(state, moves, m.GetQValues(state))
 	}
 
 	return m.getBestAction(state, moves)
@@ -74,12 +118,13 @@ func (m *MonteCarloAgent) getBestAction(state string, moves []int) int {
 
 func (m *MonteCarloAgent) Learn(state string, action int, reward float64, newState string) {
 	m.episode = append(m.episode, Episode{state, action, reward})
+	m.explorer.Observe(state, action)
 
 	// Only update at the end of the episode
 	if newState == "" {
 		m.updateEpisode()
 		m.episode = make([]Episode, 0)
-		m.epsilon = math.Max(0.1, m.epsilon*0.99995)
+		m.explorer.Step()
 	}
 }
 
@@ -117,9 +162,26 @@ func (m *MonteCarloAgent) updateEpisode() {
 			}
 			average := sum / float64(len(m.returns[exp.state][exp.action]))
 			
+			// The Monte Carlo error is measured against the full return
 			qValues := m.GetQValues(exp.state)
+			m.record(G - qValues[exp.action])
 			qValues[exp.action] = average
 			m.qTable[exp.state] = qValues
 		}
 	}
-} 
+}
+
+func (m *MonteCarloAgent) Save(filename string) error {
+	return SaveMonteCarlo(filename+".montecarlo", m.qTable, m.returns, m.Relative)
+}
+
+func (m *MonteCarloAgent) Load(filename string) error {
+	qtable, returns, relative, err := LoadMonteCarlo(filename + ".montecarlo")
+	if err != nil {
+		return err
+	}
+	m.qTable = qtable
+	m.returns = returns
+	m.Relative = relative
+	return nil
+}
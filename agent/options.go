@@ -0,0 +1,78 @@
+package agent
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Options are the hyperparameters of the tabular agents. Monte Carlo averages
+// returns instead of stepping towards them, so it ignores Alpha.
+type Options struct {
+	Epsilon    float64 `json:"epsilon"`     // initial exploration rate
+	MinEpsilon float64 `json:"min_epsilon"` // exploration floor
+	Decay      float64 `json:"decay"`       // epsilon is multiplied by Decay after each update
+	Alpha      float64 `json:"alpha"`       // learning rate
+	Gamma      float64 `json:"gamma"`       // discount factor
+	Relative   bool    `json:"relative"`    // encode states relative to the mover
+}
+
+// DefaultOptions returns the settings used by NewQAgent, NewSarsaAgent and
+// NewMonteCarloAgent
+func DefaultOptions() Options {
+	return Options{
+		Epsilon:    0.9,
+		MinEpsilon: 0.1,
+		Decay:      0.99995,
+		Alpha:      0.1,
+		Gamma:      0.99,
+		Relative:   true,
+	}
+}
+
+// Validate checks that every option is in range
+func (o Options) Validate() error {
+	switch {
+	case o.Epsilon < 0 || o.Epsilon > 1:
+		return fmt.Errorf("epsilon %g is outside [0, 1]", o.Epsilon)
+	case o.MinEpsilon < 0 || o.MinEpsilon > o.Epsilon:
+		return fmt.Errorf("min_epsilon %g is outside [0, epsilon]", o.MinEpsilon)
+	case o.Decay <= 0 || o.Decay > 1:
+		return fmt.Errorf("decay %g is outside (0, 1]", o.Decay)
+	case o.Alpha <= 0 || o.Alpha > 1:
+		return fmt.Errorf("alpha %g is outside (0, 1]", o.Alpha)
+	case o.Gamma < 0 || o.Gamma > 1:
+		return fmt.Errorf("gamma %g is outside [0, 1]", o.Gamma)
+	}
+	return nil
+}
+
+func (o Options) String() string {
+	return fmt.Sprintf("epsilon=%g min_epsilon=%g decay=%g alpha=%g gamma=%g relative=%t",
+		o.Epsilon, o.MinEpsilon, o.Decay, o.Alpha, o.Gamma, o.Relative)
+}
+
+// Params returns the options as spec settings, keyed like their JSON fields
+func (o Options) Params() *Params {
+	format := func(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) }
+	return NewParams(map[string]string{
+		"epsilon":     format(o.Epsilon),
+		"min_epsilon": format(o.MinEpsilon),
+		"decay":       format(o.Decay),
+		"alpha":       format(o.Alpha),
+		"gamma":       format(o.Gamma),
+		"relative":    strconv.FormatBool(o.Relative),
+	})
+}
+
+// optionsFrom reads the settings named as in Params over DefaultOptions
+func optionsFrom(p *Params) Options {
+	o := DefaultOptions()
+	return Options{
+		Epsilon:    p.Float("epsilon", o.Epsilon),
+		MinEpsilon: p.Float("min_epsilon", o.MinEpsilon),
+		Decay:      p.Float("decay", o.Decay),
+		Alpha:      p.Float("alpha", o.Alpha),
+		Gamma:      p.Float("gamma", o.Gamma),
+		Relative:   p.Bool("relative", o.Relative),
+	}
+}
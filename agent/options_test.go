@@ -0,0 +1,58 @@
+package agent
+
+import "testing"
+
+func TestOptionsValidate(t *testing.T) {
+	valid := DefaultOptions()
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("DefaultOptions().Validate() = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(o *Options)
+	}{
+		{"epsilon above 1", func(o *Options) { o.Epsilon = 1.5 }},
+		{"floor above epsilon", func(o *Options) { o.MinEpsilon = 0.95 }},
+		{"zero decay", func(o *Options) { o.Decay = 0 }},
+		{"zero alpha", func(o *Options) { o.Alpha = 0 }},
+		{"negative gamma", func(o *Options) { o.Gamma = -0.1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := DefaultOptions()
+			tt.modify(&o)
+			if err := o.Validate(); err == nil {
+				t.Errorf("Validate() accepted %v", o)
+			}
+		})
+	}
+}
+
+func TestWithOptionsEpsilonSchedule(t *testing.T) {
+	opts := Options{Epsilon: 0.5, MinEpsilon: 0.2, Decay: 0.5, Alpha: 0.3, Gamma: 0.9}
+	agents := map[string]interface {
+		Agent
+		Instrumented
+	}{
+		"qlearning":  NewQAgentWithOptions(1, opts),
+		"sarsa":      NewSarsaAgentWithOptions(1, opts),
+		"montecarlo": NewMonteCarloAgentWithOptions(1, opts),
+	}
+	for name, a := range agents {
+		if got := a.Epsilon(); got != 0.5 {
+			t.Errorf("%s: initial Epsilon() = %v, want 0.5", name, got)
+		}
+		a.Learn("000000000", 4, 0, "")
+		if got := a.Epsilon(); got != 0.25 {
+			t.Errorf("%s: Epsilon() after one update = %v, want 0.25", name, got)
+		}
+		a.Learn("000000000", 4, 0, "")
+		if got := a.Epsilon(); got != 0.2 {
+			t.Errorf("%s: Epsilon() after two updates = %v, want the 0.2 floor", name, got)
+		}
+	}
+	if got := NewQAgentWithOptions(1, opts).alpha; got != 0.3 {
+		t.Errorf("alpha = %v, want 0.3", got)
+	}
+}
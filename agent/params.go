@@ -0,0 +1,139 @@
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Params are the key=value settings of a spec such as "minimax:depth=3".
+// Reading a key marks it used, so settings nobody read can be reported, and
+// the first malformed value is kept until the spec is finished.
+type Params struct {
+	values map[string]string
+	used   map[string]bool
+	err    error
+}
+
+// NewParams returns params holding values
+func NewParams(values map[string]string) *Params {
+	p := &Params{values: make(map[string]string), used: make(map[string]bool)}
+	for key, value := range values {
+		p.values[key] = value
+	}
+	return p
+}
+
+// parseSpec splits name:key=value,key=value
+func parseSpec(spec string) (string, *Params, error) {
+	params := NewParams(nil)
+	name, rest, _ := strings.Cut(strings.TrimSpace(spec), ":")
+	if rest == "" {
+		return strings.ToLower(name), params, nil
+	}
+	for _, pair := range strings.Split(rest, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return "", nil, fmt.Errorf("spec %q: expected key=value, got %q", spec, pair)
+		}
+		params.values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return strings.ToLower(name), params, nil
+}
+
+// Set sets key to value
+func (p *Params) Set(key, value string) {
+	p.values[key] = value
+}
+
+// Has reports whether key is set
+func (p *Params) Has(key string) bool {
+	_, ok := p.values[key]
+	return ok
+}
+
+// Get returns the value of key, or fallback if it is not set
+func (p *Params) Get(key, fallback string) string {
+	p.used[key] = true
+	if value, ok := p.values[key]; ok {
+		return value
+	}
+	return fallback
+}
+
+// Float returns key as a number
+func (p *Params) Float(key string, fallback float64) float64 {
+	value, ok := p.values[key]
+	p.used[key] = true
+	if !ok {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	p.fail(key, err)
+	return f
+}
+
+// Int returns key as a positive integer
+func (p *Params) Int(key string, fallback int) int {
+	value, ok := p.values[key]
+	p.used[key] = true
+	if !ok {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err == nil && n <= 0 {
+		err = fmt.Errorf("must be positive")
+	}
+	p.fail(key, err)
+	return n
+}
+
+// Int64 returns key as an integer of any sign, such as a seed
+func (p *Params) Int64(key string, fallback int64) int64 {
+	value, ok := p.values[key]
+	p.used[key] = true
+	if !ok {
+		return fallback
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	p.fail(key, err)
+	return n
+}
+
+// Bool returns key as true or false
+func (p *Params) Bool(key string, fallback bool) bool {
+	value, ok := p.values[key]
+	p.used[key] = true
+	if !ok {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	p.fail(key, err)
+	return b
+}
+
+// fail keeps the first malformed value
+func (p *Params) fail(key string, err error) {
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("%s: %v", key, err)
+	}
+}
+
+// finish reports the first malformed value or unknown key
+func (p *Params) finish() error {
+	if p.err != nil {
+		return p.err
+	}
+	var unknown []string
+	for key := range p.values {
+		if !p.used[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown key %q", unknown[0])
+	}
+	return nil
+}
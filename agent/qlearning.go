@@ -1,37 +1,79 @@
 package agent
 
 import (
-	"math"
-	"math/rand"
-
 	"github.com/jpotts18/tictactoe/game"
 )
 
 type QAgent struct {
 	BaseAgent
 	qTable     map[string][]float64
-	epsilon    float64
+	explorer   Explorer
 	alpha      float64
 	gamma      float64
 	Evaluating bool
+	*tdStats
 }
 
 func NewQAgent(player int) *QAgent {
+	return NewQAgentWithOptions(player, DefaultOptions())
+}
+
+// NewQAgentWithOptions creates a Q-learning agent with the given hyperparameters
+func NewQAgentWithOptions(player int, opts Options) *QAgent {
 	return &QAgent{
-		BaseAgent:  BaseAgent{Player: player},
+		BaseAgent:  BaseAgent{Player: player, Relative: opts.Relative},
 		qTable:     make(map[string][]float64),
-		epsilon:    0.9,
-		alpha:      0.1,
-		gamma:      0.99,
+		explorer:   defaultExplorer(opts),
+		alpha:      opts.Alpha,
+		gamma:      opts.Gamma,
 		Evaluating: false,
+		tdStats:    &tdStats{},
 	}
 }
 
+// GetQValues returns the Q-values for state. Unseen states get a fresh row
+// of initial values that is not stored, so evaluating a policy does not grow
+// the table.
 func (q *QAgent) GetQValues(state string) []float64 {
-	if _, exists := q.qTable[state]; !exists {
-		q.qTable[state] = make([]float64, 9)
+	if qValues, exists := q.qTable[state]; exists {
+		return qValues
+	}
+	return newRow(q.explorer)
+}
+
+// ForSeat returns a view of the agent playing as player that shares its
+// Q-table, explorer and statistics
+func (q *QAgent) ForSeat(player int) Agent {
+	seat := *q
+	seat.Player = player
+	return &seat
+}
+
+// Seed fixes the random source of the agent's explorer
+func (q *QAgent) Seed(seed int64) {
+	if seeder, ok := q.explorer.(Seeder); ok {
+		seeder.Seed(seed)
 	}
-	return q.qTable[state]
+}
+
+// SetExplorer replaces the exploration strategy
+func (q *QAgent) SetExplorer(e Explorer) {
+	q.explorer = e
+}
+
+// Epsilon returns the current exploration rate
+func (q *QAgent) Epsilon() float64 {
+	return q.explorer.Rate()
+}
+
+// TableSize returns the number of states in the Q-table
+func (q *QAgent) TableSize() int {
+	return len(q.qTable)
+}
+
+// SetEvaluating turns exploration off (true) or back on (false)
+func (q *QAgent) SetEvaluating(evaluating bool) {
+	q.Evaluating = evaluating
 }
 
 func (q *QAgent) GetMove(board *game.Board, player int) int {
@@ -47,11 +89,7 @@ func (q *QAgent) GetMove(board *game.Board, player int) int {
 		return q.getBestAction(moves, qValues)
 	}
 
-	if rand.Float64() < q.epsilon {
-		return moves[rand.Intn(len(moves))]
-	}
-
-	return q.getBestAction(moves, qValues)
+	return q.explorer.Choose(stateKey, moves, qValues)
 }
 
 func (q *QAgent) getBestAction(moves []int, qValues []float64) int {
@@ -81,22 +119,26 @@ func (q *QAgent) Learn(state string, action int, reward float64, newState string
 		}
 	}
 
-	newValue := oldValue + q.alpha*(reward + q.gamma*maxNextQ - oldValue)
+	tdError := reward + q.gamma*maxNextQ - oldValue
+	q.record(tdError)
+	newValue := oldValue + q.alpha*tdError
 	oldQValues[action] = newValue
 	q.qTable[state] = oldQValues
-	
-	q.epsilon = math.Max(0.1, q.epsilon*0.99995)
+
+	q.explorer.Observe(state, action)
+	q.explorer.Step()
 }
 
 func (q *QAgent) Save(filename string) error {
-	return SaveQTable(filename + ".qlearning", q.qTable)
+	return SaveQTable(filename + ".qlearning", q.qTable, q.Relative)
 }
 
 func (q *QAgent) Load(filename string) error {
-	qtable, err := LoadQTable(filename + ".qlearning")
+	qtable, relative, err := LoadQTable(filename + ".qlearning")
 	if err != nil {
 		return err
 	}
 	q.qTable = qtable
+	q.Relative = relative
 	return nil
 }
@@ -18,6 +18,11 @@ func NewRandomAgent(player int) *RandomAgent {
 	}
 }
 
+// Seed fixes the random source of the agent's moves
+func (r *RandomAgent) Seed(seed int64) {
+	r.rng.Seed(seed)
+}
+
 func (r *RandomAgent) GetMove(board *game.Board, player int) int {
 	moves := board.GetAvailableMoves()
 	if len(moves) == 0 {
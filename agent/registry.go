@@ -0,0 +1,206 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Factory builds a new agent for player from the settings of a spec
+type Factory func(player int, params *Params) (Agent, error)
+
+// Loader builds an agent for player from a saved model file
+type Loader func(player int, filename string, params *Params) (Agent, error)
+
+// Registration describes an agent type to the registry. Agents with an
+// Extension but no Load are built by New and then read their model with
+// LearningAgent.Load.
+type Registration struct {
+	Name      string // spec name, e.g. "qlearning"
+	Title     string // display name, e.g. "Q-Learning"
+	New       Factory
+	Extension string // suffix of saved model files, empty if there are none
+	Load      Loader
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []Registration
+)
+
+// Register adds an agent type. Other packages register their agents from an
+// init function. It panics if the name is empty or taken, or New is nil.
+func Register(r Registration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if r.Name == "" || r.New == nil {
+		panic("agent: Register needs a name and a factory")
+	}
+	if r.Title == "" {
+		r.Title = r.Name
+	}
+	for _, existing := range registry {
+		if existing.Name == r.Name {
+			panic("agent: Register called twice for " + r.Name)
+		}
+	}
+	registry = append(registry, r)
+}
+
+// Registered returns every agent type in the order registered
+func Registered() []Registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return append([]Registration(nil), registry...)
+}
+
+// Names returns the spec names of every agent type
+func Names() []string {
+	var names []string
+	for _, r := range Registered() {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+// Lookup finds an agent type by name or title, ignoring case
+func Lookup(name string) (Registration, bool) {
+	name = strings.TrimSpace(name)
+	for _, r := range Registered() {
+		if strings.EqualFold(r.Name, name) || strings.EqualFold(r.Title, name) {
+			return r, true
+		}
+	}
+	return Registration{}, false
+}
+
+// Open builds an agent for player from a saved model. The extension may be
+// left off filename.
+func (r Registration) Open(player int, filename string, params *Params) (Agent, error) {
+	if r.Extension == "" {
+		return nil, fmt.Errorf("%s agents have no saved models", r.Name)
+	}
+	if !strings.HasSuffix(filename, r.Extension) {
+		filename += r.Extension
+	}
+	if r.Load != nil {
+		return r.Load(player, filename, params)
+	}
+	a, err := r.New(player, params)
+	if err != nil {
+		return nil, err
+	}
+	learner, ok := a.(LearningAgent)
+	if !ok {
+		return nil, fmt.Errorf("%s agents cannot load models", r.Name)
+	}
+	if err := learner.Load(strings.TrimSuffix(filename, r.Extension)); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// New builds an agent for player from a spec of the form
+// name[:model][,key=value,...]. A setting without a key is the saved model to
+// load, so "qlearning:models/qlearning" loads a trained Q-table and
+// "minimax:depth=3" builds a shallow minimax player.
+func New(spec string, player int) (Agent, error) {
+	name, rest, found := strings.Cut(strings.TrimSpace(spec), ":")
+	if found {
+		items := strings.Split(rest, ",")
+		for i, item := range items {
+			if item != "" && !strings.Contains(item, "=") {
+				items[i] = "model=" + item
+			}
+		}
+		spec = name + ":" + strings.Join(items, ",")
+	}
+	name, params, err := parseSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithParams(name, player, params)
+}
+
+// NewWithParams builds the agent type called name for player. The "model"
+// setting names a saved model to load.
+func NewWithParams(name string, player int, params *Params) (Agent, error) {
+	r, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown agent %q, expected one of %s", name, strings.Join(Names(), ", "))
+	}
+	var a Agent
+	var err error
+	if model := params.Get("model", ""); model != "" {
+		a, err = r.Open(player, model, params)
+	} else {
+		a, err = r.New(player, params)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("agent %s: %v", r.Name, err)
+	}
+	if err := params.finish(); err != nil {
+		return nil, fmt.Errorf("agent %s: %v", r.Name, err)
+	}
+	return a, nil
+}
+
+// tabular is the factory of an agent built from Options
+func tabular(newAgent func(player int, opts Options) Agent) Factory {
+	return func(player int, params *Params) (Agent, error) {
+		opts := optionsFrom(params)
+		if params.err == nil {
+			if err := opts.Validate(); err != nil {
+				return nil, err
+			}
+		}
+		return newAgent(player, opts), nil
+	}
+}
+
+// seed reads the seed setting, defaulting to the clock
+func seed(params *Params) int64 {
+	return params.Int64("seed", time.Now().UnixNano())
+}
+
+func init() {
+	Register(Registration{Name: "random", Title: "Random", New: func(player int, params *Params) (Agent, error) {
+		return NewRandomAgent(player), nil
+	}})
+	Register(Registration{Name: "minimax", Title: "Minimax", New: func(player int, params *Params) (Agent, error) {
+		return NewMinimaxAgentWithDepth(player, params.Int("depth", DefaultMinimaxDepth)), nil
+	}})
+	Register(Registration{Name: "heuristic", Title: "Heuristic", New: func(player int, params *Params) (Agent, error) {
+		return NewHeuristicAgent(player), nil
+	}})
+	for _, d := range []Difficulty{Easy, Medium, Hard, Perfect} {
+		d := d
+		Register(Registration{
+			Name:  d.String(),
+			Title: strings.ToUpper(d.String()[:1]) + d.String()[1:],
+			New: func(player int, params *Params) (Agent, error) {
+				return NewSkillAgent(player, d, seed(params)), nil
+			},
+		})
+	}
+
+	Register(Registration{Name: "qlearning", Title: "Q-Learning", Extension: ".qlearning",
+		New: tabular(func(player int, opts Options) Agent { return NewQAgentWithOptions(player, opts) })})
+	Register(Registration{Name: "sarsa", Title: "SARSA", Extension: ".sarsa",
+		New: tabular(func(player int, opts Options) Agent { return NewSarsaAgentWithOptions(player, opts) })})
+	Register(Registration{Name: "montecarlo", Title: "Monte Carlo", Extension: ".montecarlo",
+		New: tabular(func(player int, opts Options) Agent { return NewMonteCarloAgentWithOptions(player, opts) })})
+	Register(Registration{Name: "actorcritic", Title: "Actor-Critic", Extension: ".actorcritic",
+		New: func(player int, params *Params) (Agent, error) {
+			return NewActorCriticAgent(player), nil
+		}})
+	Register(Registration{Name: "dqn", Title: "DQN", Extension: ".dqn",
+		New: func(player int, params *Params) (Agent, error) {
+			return NewDQNAgent(player, seed(params)), nil
+		}})
+	Register(Registration{Name: "menace", Title: "MENACE", Extension: ".menace",
+		New: func(player int, params *Params) (Agent, error) {
+			return NewMenaceAgent(player, DefaultBeadRules()), nil
+		}})
+}
@@ -0,0 +1,95 @@
+package agent
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		spec    string
+		check   func(a Agent) bool
+		wantErr bool
+	}{
+		{"random", func(a Agent) bool { _, ok := a.(*RandomAgent); return ok }, false},
+		{"minimax", func(a Agent) bool { return a.(*MinimaxAgent).Depth == DefaultMinimaxDepth }, false},
+		{"minimax:depth=2", func(a Agent) bool { return a.(*MinimaxAgent).Depth == 2 }, false},
+		{"Minimax:depth=2", func(a Agent) bool { return a.(*MinimaxAgent).Depth == 2 }, false},
+		{"perfect", func(a Agent) bool { return a.(*SkillAgent).BlunderRate == 0 }, false},
+		{"easy:seed=3", func(a Agent) bool { return a.(*SkillAgent).BlunderRate == Easy.BlunderRate() }, false},
+		{"qlearning:alpha=0.5", func(a Agent) bool { return a.(*QAgent).alpha == 0.5 }, false},
+		{"grandmaster", nil, true},
+		{"minimax:depth=0", nil, true},
+		{"minimax:depth=deep", nil, true},
+		{"minimax:width=3", nil, true},
+		{"random:depth=2", nil, true},
+		{"qlearning:alpha=2", nil, true},
+		{"qlearning:models/missing", nil, true},
+		{"minimax:models/minimax", nil, true},
+	}
+	for _, tt := range tests {
+		a, err := New(tt.spec, 2)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("New(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if err == nil && !tt.check(a) {
+			t.Errorf("New(%q) = %#v, not the agent the spec describes", tt.spec, a)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, name := range []string{"qlearning", "Q-Learning", "q-learning"} {
+		if r, ok := Lookup(name); !ok || r.Name != "qlearning" || r.Extension != ".qlearning" {
+			t.Errorf("Lookup(%q) = %+v, %v, want the qlearning registration", name, r, ok)
+		}
+	}
+	if _, ok := Lookup("alphago"); ok {
+		t.Error("Lookup(\"alphago\") found an agent")
+	}
+}
+
+func TestNewLoadsModel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "q")
+	q := NewQAgent(1)
+	q.Learn("000000000", 4, 1, "000010000")
+	if err := q.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, spec := range []string{"qlearning:" + path, "qlearning:" + path + ".qlearning", "qlearning:model=" + path} {
+		a, err := New(spec, 1)
+		if err != nil {
+			t.Errorf("New(%q) error = %v", spec, err)
+			continue
+		}
+		if got, want := a.(*QAgent).GetQValues("000000000"), q.GetQValues("000000000"); !reflect.DeepEqual(got, want) {
+			t.Errorf("New(%q) loaded Q-values %v, want %v", spec, got, want)
+		}
+	}
+}
+
+func TestOptionsParams(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Alpha, opts.Relative = 0.25, true
+	params := opts.Params()
+	if got := optionsFrom(params); got != opts {
+		t.Errorf("optionsFrom(Params()) = %+v, want %+v", got, opts)
+	}
+	if err := params.finish(); err != nil {
+		t.Errorf("finish() = %v, want every option read", err)
+	}
+}
+
+func TestRegisterTwicePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() of a taken name did not panic")
+		}
+	}()
+	Register(Registration{Name: "random", New: func(player int, params *Params) (Agent, error) {
+		return NewRandomAgent(player), nil
+	}})
+}
@@ -0,0 +1,49 @@
+package agent
+
+import "math/rand"
+
+// Transition is a single (s, a, r, s') experience
+type Transition struct {
+	State     string
+	Action    int
+	Reward    float64
+	NextState string
+	Done      bool
+}
+
+// ReplayBuffer is a fixed-size ring buffer of transitions
+type ReplayBuffer struct {
+	transitions []Transition
+	capacity    int
+	next        int
+}
+
+func NewReplayBuffer(capacity int) *ReplayBuffer {
+	return &ReplayBuffer{
+		transitions: make([]Transition, 0, capacity),
+		capacity:    capacity,
+	}
+}
+
+// Add stores a transition, overwriting the oldest one once the buffer is full
+func (r *ReplayBuffer) Add(t Transition) {
+	if len(r.transitions) < r.capacity {
+		r.transitions = append(r.transitions, t)
+	} else {
+		r.transitions[r.next] = t
+	}
+	r.next = (r.next + 1) % r.capacity
+}
+
+func (r *ReplayBuffer) Len() int {
+	return len(r.transitions)
+}
+
+// Sample draws n transitions uniformly with replacement
+func (r *ReplayBuffer) Sample(rng *rand.Rand, n int) []Transition {
+	batch := make([]Transition, n)
+	for i := range batch {
+		batch[i] = r.transitions[rng.Intn(len(r.transitions))]
+	}
+	return batch
+}
@@ -1,41 +1,86 @@
 package agent
 
 import (
-	"math"
-	"math/rand"
-
 	"github.com/jpotts18/tictactoe/game"
 )
 
 type SarsaAgent struct {
 	BaseAgent
 	qTable     map[string][]float64
-	epsilon    float64
+	explorer   Explorer
 	alpha      float64
 	gamma      float64
 	lastState  string
 	lastAction int
+	lastReward float64
 	Evaluating bool
+	*tdStats
 }
 
 func NewSarsaAgent(player int) *SarsaAgent {
+	return NewSarsaAgentWithOptions(player, DefaultOptions())
+}
+
+// NewSarsaAgentWithOptions creates a SARSA agent with the given hyperparameters
+func NewSarsaAgentWithOptions(player int, opts Options) *SarsaAgent {
 	return &SarsaAgent{
-		BaseAgent:  BaseAgent{Player: player},
+		BaseAgent:  BaseAgent{Player: player, Relative: opts.Relative},
 		qTable:     make(map[string][]float64),
-		epsilon:    0.9,
-		alpha:      0.1,
-		gamma:      0.99,
+		explorer:   defaultExplorer(opts),
+		alpha:      opts.Alpha,
+		gamma:      opts.Gamma,
 		lastState:  "",
 		lastAction: -1,
 		Evaluating: false,
+		tdStats:    &tdStats{},
 	}
 }
 
+// GetQValues returns the Q-values for state. Unseen states get a fresh row
+// of initial values that is not stored, so evaluating a policy does not grow
+// the table.
 func (s *SarsaAgent) GetQValues(state string) []float64 {
-	if _, exists := s.qTable[state]; !exists {
-		s.qTable[state] = make([]float64, 9)
+	if qValues, exists := s.qTable[state]; exists {
+		return qValues
+	}
+	return newRow(s.explorer)
+}
+
+// ForSeat returns a view of the agent playing as player that shares its
+// Q-table, explorer and statistics but has its own pending transition
+func (s *SarsaAgent) ForSeat(player int) Agent {
+	seat := *s
+	seat.Player = player
+	seat.lastState = ""
+	seat.lastAction = -1
+	return &seat
+}
+
+// Seed fixes the random source of the agent's explorer
+func (s *SarsaAgent) Seed(seed int64) {
+	if seeder, ok := s.explorer.(Seeder); ok {
+		seeder.Seed(seed)
 	}
-	return s.qTable[state]
+}
+
+// SetExplorer replaces the exploration strategy
+func (s *SarsaAgent) SetExplorer(e Explorer) {
+	s.explorer = e
+}
+
+// Epsilon returns the current exploration rate
+func (s *SarsaAgent) Epsilon() float64 {
+	return s.explorer.Rate()
+}
+
+// TableSize returns the number of states in the Q-table
+func (s *SarsaAgent) TableSize() int {
+	return len(s.qTable)
+}
+
+// SetEvaluating turns exploration off (true) or back on (false)
+func (s *SarsaAgent) SetEvaluating(evaluating bool) {
+	s.Evaluating = evaluating
 }
 
 func (s *SarsaAgent) GetMove(board *game.Board, player int) int {
@@ -46,8 +91,8 @@ func (s *SarsaAgent) GetMove(board *game.Board, player int) int {
 		return -1
 	}
 
-	if rand.Float64() < s.epsilon {
-		return moves[rand.Intn(len(moves))]
+	if !s.Evaluating {
+		return s.explorer.Choose(state, moves, s.GetQValues(state))
 	}
 
 	return s.getBestAction(state, moves)
@@ -67,37 +112,46 @@ func (s *SarsaAgent) getBestAction(state string, moves []int) int {
 	return bestMove
 }
 
+// Learn completes the previous transition now that the action taken after
+// it is known, updating Q(s, a) towards r + gamma*Q(s', a'). A terminal
+// transition (newState == "") is updated straight away.
 func (s *SarsaAgent) Learn(state string, action int, reward float64, newState string) {
 	if s.lastState != "" {
-		currentQValues := s.GetQValues(newState)
-		nextAction := -1
-		
-		if newState != "" {
-			boardState := game.FromStateString(newState)
-			moves := boardState.GetAvailableMoves()
-			if len(moves) > 0 {
-				if rand.Float64() < s.epsilon {
-					nextAction = moves[rand.Intn(len(moves))]
-				} else {
-					nextAction = s.getBestAction(state, moves)
-				}
-			}
-		}
-
-		oldQValues := s.GetQValues(s.lastState)
-		oldValue := oldQValues[s.lastAction]
-		
-		var nextQValue float64
-		if nextAction != -1 {
-			nextQValue = currentQValues[nextAction]
-		}
+		s.update(s.lastState, s.lastAction, s.lastReward+s.gamma*s.GetQValues(state)[action])
+	}
 
-		newValue := oldValue + s.alpha*(reward + s.gamma*nextQValue - oldValue)
-		oldQValues[s.lastAction] = newValue
-		s.qTable[s.lastState] = oldQValues
+	if newState == "" {
+		s.update(state, action, reward)
+		s.lastState = ""
+		s.lastAction = -1
+	} else {
+		s.lastState = state
+		s.lastAction = action
+		s.lastReward = reward
 	}
+	s.explorer.Observe(state, action)
+	s.explorer.Step()
+}
 
-	s.lastState = state
-	s.lastAction = action
-	s.epsilon = math.Max(0.1, s.epsilon*0.99995)
+// update moves Q(state, action) towards target
+func (s *SarsaAgent) update(state string, action int, target float64) {
+	qValues := s.GetQValues(state)
+	tdError := target - qValues[action]
+	s.record(tdError)
+	qValues[action] += s.alpha * tdError
+	s.qTable[state] = qValues
+}
+
+func (s *SarsaAgent) Save(filename string) error {
+	return SaveQTable(filename+".sarsa", s.qTable, s.Relative)
+}
+
+func (s *SarsaAgent) Load(filename string) error {
+	qtable, relative, err := LoadQTable(filename + ".sarsa")
+	if err != nil {
+		return err
+	}
+	s.qTable = qtable
+	s.Relative = relative
+	return nil
 }
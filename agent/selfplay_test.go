@@ -0,0 +1,199 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+// playSeats plays moves alternately, X first, handing each seat its own
+// transitions the way training.Manager does. If finish is set the game is
+// ended and both seats get their final reward: 1 for a win, -1 for a loss.
+func playSeats(x, o Agent, moves []int, finish bool) {
+	board := game.NewBoard()
+	seats := [3]Agent{nil, x, o}
+	type move struct {
+		state  string
+		action int
+	}
+	var pending [3]*move
+	player := 1
+	for _, action := range moves {
+		a := seats[player]
+		if p := pending[player]; p != nil {
+			a.Learn(p.state, p.action, 0, a.GetStateKey(board))
+		}
+		pending[player] = &move{a.GetStateKey(board), action}
+		board.MakeMove(action, player)
+		player = 3 - player
+	}
+	if !finish {
+		return
+	}
+	_, winner := board.IsGameOver()
+	for player := 1; player <= 2; player++ {
+		r := 0.0
+		if winner == player {
+			r = 1
+		} else if winner != 0 {
+			r = -1
+		}
+		seats[player].Learn(pending[player].state, pending[player].action, r, "")
+	}
+}
+
+// X takes the top row; O plays the center and a corner
+var selfPlayMoves = []int{0, 4, 1, 8, 2}
+
+func TestMonteCarloSeatsKeepTheirOwnEpisodes(t *testing.T) {
+	m := NewMonteCarloAgent(1)
+	x, o := m.ForSeat(1).(*MonteCarloAgent), m.ForSeat(2).(*MonteCarloAgent)
+	playSeats(x, o, selfPlayMoves[:4], false)
+
+	// A move joins the episode once its seat moves again. O's states are
+	// seen from its side, with its marks as 1.
+	tests := []struct {
+		seat *MonteCarloAgent
+		want []Episode
+	}{
+		{x, []Episode{{"000000000", 0, 0}}},
+		{o, []Episode{{"200000000", 4, 0}}},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.seat.episode, tt.want) {
+			t.Errorf("seat %d episode = %v, want %v", tt.seat.Player, tt.seat.episode, tt.want)
+		}
+	}
+	if len(m.episode) != 0 {
+		t.Errorf("original agent recorded %v, want nothing", m.episode)
+	}
+
+	playSeats(m.ForSeat(1), m.ForSeat(2), selfPlayMoves, true)
+	if got := m.GetQValues("110020002")[2]; got != 1 {
+		t.Errorf("shared Q(X's winning move) = %v, want 1", got)
+	}
+	if got := m.GetQValues("220010000")[8]; got >= 0 {
+		t.Errorf("shared Q(O's losing move) = %v, want < 0", got)
+	}
+}
+
+func TestSarsaSeatsKeepTheirOwnTransitions(t *testing.T) {
+	s := NewSarsaAgent(1)
+	x, o := s.ForSeat(1).(*SarsaAgent), s.ForSeat(2).(*SarsaAgent)
+	playSeats(x, o, selfPlayMoves[:4], false)
+
+	if x.lastState != "000000000" || x.lastAction != 0 {
+		t.Errorf("X's pending transition = %s/%d, want its opening move 000000000/0", x.lastState, x.lastAction)
+	}
+	if o.lastState != "200000000" || o.lastAction != 4 {
+		t.Errorf("O's pending transition = %s/%d, want its first move 200000000/4", o.lastState, o.lastAction)
+	}
+
+	// Both seats' terminal updates land in the shared table
+	playSeats(s.ForSeat(1), s.ForSeat(2), selfPlayMoves, true)
+	if got := s.GetQValues("110020002")[2]; got <= 0 {
+		t.Errorf("shared Q(X's winning move) = %v, want > 0", got)
+	}
+	if got := s.GetQValues("220010000")[8]; got >= 0 {
+		t.Errorf("shared Q(O's losing move) = %v, want < 0", got)
+	}
+	if s.lastState != "" {
+		t.Errorf("original agent has pending state %q, want none", s.lastState)
+	}
+}
+
+func TestSeatsShareStatistics(t *testing.T) {
+	q := NewQAgent(1)
+	playSeats(q.ForSeat(1), q.ForSeat(2), selfPlayMoves, true)
+	if q.TableSize() != 5 {
+		t.Errorf("TableSize() = %d, want the 5 states both seats moved from", q.TableSize())
+	}
+	if q.MeanAbsTDError() == 0 {
+		t.Errorf("MeanAbsTDError() = 0, want the seats' updates recorded")
+	}
+}
+
+func TestRelativeStateKey(t *testing.T) {
+	board := game.FromStateString("120010002")
+	tests := []struct {
+		base BaseAgent
+		want string
+	}{
+		{BaseAgent{Player: 1}, "120010002"},
+		{BaseAgent{Player: 2}, "120010002"},
+		{BaseAgent{Player: 1, Relative: true}, "120010002"},
+		{BaseAgent{Player: 2, Relative: true}, "210020001"},
+	}
+	for _, tt := range tests {
+		if got := tt.base.GetStateKey(board); got != tt.want {
+			t.Errorf("%+v: GetStateKey() = %s, want %s", tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestRelativeEncodingServesBothSeats(t *testing.T) {
+	// The table says: with two in the top row and the third cell free, take it
+	learned := map[string][]float64{"110220000": {0, 0, 1, 0, 0, 0, 0, 0, 0}}
+
+	// O to move with O on 0 and 1: the same position from O's side
+	board := game.FromStateString("220110000")
+
+	relative := NewQAgent(1)
+	relative.qTable = learned
+	relative.SetEvaluating(true)
+	relative.SetPlayer(2)
+	if move := relative.GetMove(board, 2); move != 2 {
+		t.Errorf("relative agent as O: GetMove() = %d, want the winning move 2", move)
+	}
+
+	opts := DefaultOptions()
+	opts.Relative = false
+	absolute := NewQAgentWithOptions(2, opts)
+	absolute.qTable = learned
+	absolute.SetEvaluating(true)
+	if key := absolute.GetStateKey(board); key != "220110000" {
+		t.Errorf("absolute agent as O: GetStateKey() = %s, want the board unchanged", key)
+	}
+}
+
+func TestLoadRestoresEncoding(t *testing.T) {
+	dir := t.TempDir()
+	opts := DefaultOptions()
+	opts.Relative = false
+	absolute := NewQAgentWithOptions(2, opts)
+	absolute.qTable["220110000"] = []float64{0, 0, 1, 0, 0, 0, 0, 0, 0}
+	if err := absolute.Save(filepath.Join(dir, "absolute")); err != nil {
+		t.Fatal(err)
+	}
+	mc := NewMonteCarloAgentWithOptions(2, opts)
+	if err := mc.Save(filepath.Join(dir, "absolute")); err != nil {
+		t.Fatal(err)
+	}
+	// Written before the encoding was stored
+	legacy := `{"qtable":{"220110000":[0,0,1,0,0,0,0,0,0]}}`
+	if err := os.WriteFile(filepath.Join(dir, "legacy.sarsa"), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		agent LearningAgent
+		file  string
+	}{
+		{"q-learning", NewQAgent(2), "absolute"},
+		{"monte carlo", NewMonteCarloAgent(2), "absolute"},
+		{"legacy sarsa", NewSarsaAgent(2), "legacy"},
+	}
+	for _, tt := range tests {
+		if err := tt.agent.Load(filepath.Join(dir, tt.file)); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		board := game.FromStateString("220110000")
+		if key := tt.agent.GetStateKey(board); key != "220110000" {
+			t.Errorf("%s: GetStateKey() = %s after loading an absolute model, want the board unchanged", tt.name, key)
+		}
+	}
+}
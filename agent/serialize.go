@@ -5,35 +5,128 @@ import (
 	"os"
 )
 
+// QTableData is a saved Q-table. Relative records the state encoding it was
+// learned with; files written before it was stored used absolute keys.
 type QTableData struct {
-	QTable map[string][]float64 `json:"qtable"`
+	QTable   map[string][]float64 `json:"qtable"`
+	Relative bool                 `json:"relative"`
 }
 
 type MonteCarloData struct {
-	QTable  map[string][]float64         `json:"qtable"`
-	Returns map[string]map[int][]float64 `json:"returns"`
+	QTable   map[string][]float64         `json:"qtable"`
+	Returns  map[string]map[int][]float64 `json:"returns"`
+	Relative bool                         `json:"relative"`
 }
 
-func SaveQTable(filename string, data map[string][]float64) error {
+func SaveQTable(filename string, data map[string][]float64, relative bool) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	return json.NewEncoder(file).Encode(QTableData{QTable: data})
+	return json.NewEncoder(file).Encode(QTableData{QTable: data, Relative: relative})
 }
 
-func LoadQTable(filename string) (map[string][]float64, error) {
+// LoadQTable reads a Q-table and whether its states are relative
+func LoadQTable(filename string) (map[string][]float64, bool, error) {
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer file.Close()
 
 	var data QTableData
 	if err := json.NewDecoder(file).Decode(&data); err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	return data.QTable, nil
+	return data.QTable, data.Relative, nil
 } 
+
+func SaveMonteCarlo(filename string, qtable map[string][]float64, returns map[string]map[int][]float64, relative bool) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return json.NewEncoder(file).Encode(MonteCarloData{QTable: qtable, Returns: returns, Relative: relative})
+}
+
+// LoadMonteCarlo reads a Monte Carlo model and whether its states are relative
+func LoadMonteCarlo(filename string) (map[string][]float64, map[string]map[int][]float64, bool, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer file.Close()
+
+	var data MonteCarloData
+	if err := json.NewDecoder(file).Decode(&data); err != nil {
+		return nil, nil, false, err
+	}
+	if data.Returns == nil {
+		data.Returns = make(map[string]map[int][]float64)
+	}
+	return data.QTable, data.Returns, data.Relative, nil
+}
+
+type ActorCriticData struct {
+	Preferences map[string][]float64 `json:"preferences"`
+	Values      map[string]float64   `json:"values"`
+	Relative    bool                 `json:"relative"`
+}
+
+func SaveActorCritic(filename string, preferences map[string][]float64, values map[string]float64, relative bool) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return json.NewEncoder(file).Encode(ActorCriticData{Preferences: preferences, Values: values, Relative: relative})
+}
+
+// LoadActorCritic reads an actor-critic model and whether its states are relative
+func LoadActorCritic(filename string) (map[string][]float64, map[string]float64, bool, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer file.Close()
+
+	var data ActorCriticData
+	if err := json.NewDecoder(file).Decode(&data); err != nil {
+		return nil, nil, false, err
+	}
+	return data.Preferences, data.Values, data.Relative, nil
+}
+
+type MenaceData struct {
+	Boxes map[string][]int `json:"boxes"`
+	Rules BeadRules        `json:"rules"`
+}
+
+func SaveMenace(filename string, boxes map[string][]int, rules BeadRules) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return json.NewEncoder(file).Encode(MenaceData{Boxes: boxes, Rules: rules})
+}
+
+func LoadMenace(filename string) (map[string][]int, BeadRules, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, BeadRules{}, err
+	}
+	defer file.Close()
+
+	var data MenaceData
+	if err := json.NewDecoder(file).Decode(&data); err != nil {
+		return nil, BeadRules{}, err
+	}
+	return data.Boxes, data.Rules, nil
+}
@@ -0,0 +1,34 @@
+package agent
+
+import "math"
+
+// Instrumented is implemented by learning agents that report training
+// statistics for learning curves
+type Instrumented interface {
+	Epsilon() float64
+	TableSize() int
+
+	// MeanAbsTDError returns the mean absolute TD error of the updates made
+	// since the last call, and starts a new window
+	MeanAbsTDError() float64
+}
+
+// tdStats accumulates absolute TD errors between calls to MeanAbsTDError
+type tdStats struct {
+	sum   float64
+	count int
+}
+
+func (s *tdStats) record(tdError float64) {
+	s.sum += math.Abs(tdError)
+	s.count++
+}
+
+func (s *tdStats) MeanAbsTDError() float64 {
+	if s.count == 0 {
+		return 0
+	}
+	mean := s.sum / float64(s.count)
+	s.sum, s.count = 0, 0
+	return mean
+}
@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+// tabularAgent is implemented by the Q-table agents
+type tabularAgent interface {
+	EvaluatingAgent
+	GetQValues(state string) []float64
+}
+
+func TestTabularEvaluatingIsGreedy(t *testing.T) {
+	agents := map[string]func() tabularAgent{
+		"qlearning":  func() tabularAgent { return NewQAgent(1) },
+		"sarsa":      func() tabularAgent { return NewSarsaAgent(1) },
+		"montecarlo": func() tabularAgent { return NewMonteCarloAgent(1) },
+	}
+	for name, newAgent := range agents {
+		t.Run(name, func(t *testing.T) {
+			a := newAgent()
+			board := game.NewBoard()
+			state := a.GetStateKey(board)
+			qValues := make([]float64, 9)
+			qValues[7] = 1
+			switch a := a.(type) {
+			case *QAgent:
+				a.qTable[state] = qValues
+			case *SarsaAgent:
+				a.qTable[state] = qValues
+			case *MonteCarloAgent:
+				a.qTable[state] = qValues
+			}
+
+			a.SetEvaluating(true)
+			for i := 0; i < 50; i++ {
+				if move := a.GetMove(board, 1); move != 7 {
+					t.Fatalf("GetMove() = %v while evaluating, want the greedy move 7", move)
+				}
+			}
+		})
+	}
+}
+
+func TestGetQValuesDoesNotGrowTable(t *testing.T) {
+	q := NewQAgent(1)
+	q.SetEvaluating(true)
+	board := game.NewBoard()
+	q.GetMove(board, 1)
+	q.GetQValues("120000000")
+	if len(q.qTable) != 0 {
+		t.Errorf("qTable has %d entries after lookups, want 0", len(q.qTable))
+	}
+
+	q.Learn("000000000", 4, 1, "")
+	if got := q.GetQValues("000000000")[4]; got <= 0 {
+		t.Errorf("Q-value after a rewarded update = %v, want > 0", got)
+	}
+}
+
+func TestTabularInstrumented(t *testing.T) {
+	q := NewQAgent(1)
+	q.Learn("000000000", 4, 1, "")
+	q.Learn("000010000", 0, -1, "")
+
+	// Both updates start from a zero Q-value, so the errors are the rewards
+	if got := q.MeanAbsTDError(); got != 1 {
+		t.Errorf("MeanAbsTDError() = %v, want 1", got)
+	}
+	if got := q.MeanAbsTDError(); got != 0 {
+		t.Errorf("MeanAbsTDError() after reset = %v, want 0", got)
+	}
+	if got := q.TableSize(); got != 2 {
+		t.Errorf("TableSize() = %v, want 2", got)
+	}
+	if got := q.Epsilon(); got >= 0.9 {
+		t.Errorf("Epsilon() = %v, want it to decay below 0.9", got)
+	}
+
+	var _ Instrumented = NewSarsaAgent(1)
+	var _ Instrumented = NewMonteCarloAgent(1)
+}
@@ -0,0 +1,35 @@
+package alphazero
+
+import (
+	"math/rand"
+
+	"github.com/jpotts18/tictactoe/agent"
+	"github.com/jpotts18/tictactoe/game"
+)
+
+// Agent plays tic-tac-toe by running a greedy PUCT search with a trained model
+type Agent struct {
+	agent.BaseAgent
+	search *Search
+}
+
+func NewAgent(player int, model Model, simulations int, seed int64) *Agent {
+	search := NewSearch(model, simulations, rand.New(rand.NewSource(seed)))
+	search.NoiseFraction = 0
+	return &Agent{
+		BaseAgent: agent.BaseAgent{Player: player},
+		search:    search,
+	}
+}
+
+func (a *Agent) GetMove(board *game.Board, player int) int {
+	state := NewTicTacToeState(board, player)
+	if len(state.LegalMoves()) == 0 {
+		return -1
+	}
+	return sample(a.search.rng, Policy(a.search.VisitCounts(state), 0))
+}
+
+// Learn is a no-op; the model is trained by the self-play pipeline
+func (a *Agent) Learn(oldState string, action int, reward float64, newState string) {
+}
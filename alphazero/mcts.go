@@ -0,0 +1,216 @@
+package alphazero
+
+import (
+	"math"
+	"math/rand"
+)
+
+type node struct {
+	prior    float64
+	visits   int
+	valueSum float64
+	children map[int]*node
+}
+
+// value is the mean outcome for the player who moved into this node
+func (n *node) value() float64 {
+	if n.visits == 0 {
+		return 0
+	}
+	return n.valueSum / float64(n.visits)
+}
+
+// Search runs PUCT tree search guided by a model
+type Search struct {
+	Model       Model
+	Simulations int
+	CPuct       float64
+
+	// DirichletAlpha and NoiseFraction mix exploration noise into the root
+	// priors; set NoiseFraction to 0 for evaluation
+	DirichletAlpha float64
+	NoiseFraction  float64
+
+	rng *rand.Rand
+}
+
+func NewSearch(model Model, simulations int, rng *rand.Rand) *Search {
+	return &Search{
+		Model:          model,
+		Simulations:    simulations,
+		CPuct:          1.5,
+		DirichletAlpha: 0.5,
+		NoiseFraction:  0.25,
+		rng:            rng,
+	}
+}
+
+// VisitCounts runs the search from root and returns how often each action was visited
+func (s *Search) VisitCounts(root State) []int {
+	counts := make([]int, root.ActionSize())
+	if len(root.LegalMoves()) == 0 {
+		return counts
+	}
+
+	tree := &node{}
+	s.expand(tree, root)
+	if s.NoiseFraction > 0 {
+		s.addNoise(tree)
+	}
+
+	for i := 0; i < s.Simulations; i++ {
+		s.simulate(tree, root)
+	}
+
+	for action, child := range tree.children {
+		counts[action] = child.visits
+	}
+	return counts
+}
+
+// simulate descends to a leaf, evaluates it and backs the value up. It returns
+// the value from the perspective of the player to move at n.
+func (s *Search) simulate(n *node, state State) float64 {
+	if over, outcome := state.Outcome(); over {
+		n.visits++
+		n.valueSum -= outcome
+		return outcome
+	}
+
+	if n.children == nil {
+		value := s.expand(n, state)
+		n.visits++
+		n.valueSum -= value
+		return value
+	}
+
+	action, child := s.selectChild(n)
+	value := -s.simulate(child, state.Play(action))
+	n.visits++
+	n.valueSum -= value
+	return value
+}
+
+// expand creates children with the model's priors and returns its value estimate
+func (s *Search) expand(n *node, state State) float64 {
+	priors, value := s.Model.Predict(state)
+	n.children = make(map[int]*node)
+	for _, move := range state.LegalMoves() {
+		n.children[move] = &node{prior: priors[move]}
+	}
+	return value
+}
+
+func (s *Search) selectChild(n *node) (int, *node) {
+	bestAction := -1
+	var bestChild *node
+	bestScore := math.Inf(-1)
+	sqrtVisits := math.Sqrt(float64(n.visits))
+
+	// Iterate in action order so the search is deterministic under a seed
+	for action := 0; action <= maxAction(n.children); action++ {
+		child, exists := n.children[action]
+		if !exists {
+			continue
+		}
+		score := child.value() + s.CPuct*child.prior*sqrtVisits/float64(1+child.visits)
+		if score > bestScore {
+			bestAction, bestChild, bestScore = action, child, score
+		}
+	}
+	return bestAction, bestChild
+}
+
+func maxAction(children map[int]*node) int {
+	max := 0
+	for action := range children {
+		if action > max {
+			max = action
+		}
+	}
+	return max
+}
+
+func (s *Search) addNoise(n *node) {
+	actions := make([]int, 0, len(n.children))
+	for action := 0; action <= maxAction(n.children); action++ {
+		if _, exists := n.children[action]; exists {
+			actions = append(actions, action)
+		}
+	}
+
+	noise := make([]float64, len(actions))
+	sum := 0.0
+	for i := range noise {
+		noise[i] = sampleGamma(s.rng, s.DirichletAlpha)
+		sum += noise[i]
+	}
+	for i, action := range actions {
+		child := n.children[action]
+		child.prior = (1-s.NoiseFraction)*child.prior + s.NoiseFraction*noise[i]/sum
+	}
+}
+
+// sampleGamma draws from Gamma(alpha, 1) using Marsaglia and Tsang's method
+func sampleGamma(rng *rand.Rand, alpha float64) float64 {
+	if alpha < 1 {
+		return sampleGamma(rng, alpha+1) * math.Pow(rng.Float64(), 1/alpha)
+	}
+	d := alpha - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// Policy turns visit counts into a distribution. A temperature of zero puts
+// all mass on the most visited action.
+func Policy(counts []int, temperature float64) []float64 {
+	policy := make([]float64, len(counts))
+	if temperature == 0 {
+		best := 0
+		for action, count := range counts {
+			if count > counts[best] {
+				best = action
+			}
+		}
+		policy[best] = 1
+		return policy
+	}
+
+	sum := 0.0
+	for action, count := range counts {
+		policy[action] = math.Pow(float64(count), 1/temperature)
+		sum += policy[action]
+	}
+	for action := range policy {
+		policy[action] /= sum
+	}
+	return policy
+}
+
+// sample draws an action from a distribution
+func sample(rng *rand.Rand, policy []float64) int {
+	r := rng.Float64()
+	last := 0
+	for action, p := range policy {
+		if p == 0 {
+			continue
+		}
+		last = action
+		r -= p
+		if r <= 0 {
+			return action
+		}
+	}
+	return last
+}
@@ -0,0 +1,247 @@
+package alphazero
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"os"
+
+	"github.com/jpotts18/tictactoe/nn"
+)
+
+// Example is one training target produced by self-play
+type Example struct {
+	Key    string    `json:"key"`
+	Input  []float64 `json:"input"`
+	Legal  []int     `json:"legal"`
+	Policy []float64 `json:"policy"`
+	Value  float64   `json:"value"`
+}
+
+// Model predicts move priors and a value for the player to move
+type Model interface {
+	// Predict returns a prior over all actions (zero for illegal ones) and the
+	// expected outcome for the player to move
+	Predict(s State) ([]float64, float64)
+
+	// Train fits the model to a batch of self-play examples
+	Train(examples []Example)
+
+	// Clone returns an independent copy that can be trained without
+	// disturbing the original
+	Clone() Model
+
+	Save(filename string) error
+}
+
+// maskedPriors normalizes probabilities over the legal moves only, falling
+// back to uniform when the model puts no mass on any of them
+func maskedPriors(probs []float64, legal []int) []float64 {
+	priors := make([]float64, len(probs))
+	sum := 0.0
+	for _, move := range legal {
+		priors[move] = probs[move]
+		sum += probs[move]
+	}
+	for _, move := range legal {
+		if sum > 0 {
+			priors[move] /= sum
+		} else {
+			priors[move] = 1 / float64(len(legal))
+		}
+	}
+	return priors
+}
+
+type tabularEntry struct {
+	Policy []float64 `json:"policy"`
+	Value  float64   `json:"value"`
+}
+
+// TabularModel stores a running average policy and value for every position seen
+type TabularModel struct {
+	Entries      map[string]*tabularEntry `json:"entries"`
+	LearningRate float64                  `json:"learning_rate"`
+	actionSize   int
+}
+
+func NewTabularModel(actionSize int) *TabularModel {
+	return &TabularModel{
+		Entries:      make(map[string]*tabularEntry),
+		LearningRate: 0.3,
+		actionSize:   actionSize,
+	}
+}
+
+func (m *TabularModel) Predict(s State) ([]float64, float64) {
+	legal := s.LegalMoves()
+	entry, exists := m.Entries[s.Key()]
+	if !exists {
+		return maskedPriors(make([]float64, s.ActionSize()), legal), 0
+	}
+	return maskedPriors(entry.Policy, legal), entry.Value
+}
+
+func (m *TabularModel) Train(examples []Example) {
+	for _, ex := range examples {
+		entry, exists := m.Entries[ex.Key]
+		if !exists {
+			m.Entries[ex.Key] = &tabularEntry{
+				Policy: append([]float64(nil), ex.Policy...),
+				Value:  ex.Value,
+			}
+			continue
+		}
+		for i := range entry.Policy {
+			entry.Policy[i] += m.LearningRate * (ex.Policy[i] - entry.Policy[i])
+		}
+		entry.Value += m.LearningRate * (ex.Value - entry.Value)
+	}
+}
+
+func (m *TabularModel) Clone() Model {
+	clone := NewTabularModel(m.actionSize)
+	clone.LearningRate = m.LearningRate
+	for key, entry := range m.Entries {
+		clone.Entries[key] = &tabularEntry{
+			Policy: append([]float64(nil), entry.Policy...),
+			Value:  entry.Value,
+		}
+	}
+	return clone
+}
+
+func (m *TabularModel) Save(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return json.NewEncoder(file).Encode(m)
+}
+
+func LoadTabularModel(filename string, actionSize int) (*TabularModel, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	m := NewTabularModel(actionSize)
+	if err := json.NewDecoder(file).Decode(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// NetworkModel uses a policy network with softmax output and a value network
+// with tanh output, both built on the nn package
+type NetworkModel struct {
+	policy    *nn.Network
+	value     *nn.Network
+	policyOpt nn.Optimizer
+	valueOpt  nn.Optimizer
+	epochs    int
+}
+
+// NewNetworkModel creates policy and value networks for the given input and
+// action sizes, initialized from rng
+func NewNetworkModel(inputSize, actionSize int, rng *rand.Rand) *NetworkModel {
+	return &NetworkModel{
+		policy:    nn.NewNetwork([]int{inputSize, 64, actionSize}, nn.ReLU, nn.Linear, rng),
+		value:     nn.NewNetwork([]int{inputSize, 64, 1}, nn.ReLU, nn.Tanh, rng),
+		policyOpt: nn.NewAdam(0.005),
+		valueOpt:  nn.NewAdam(0.005),
+		epochs:    10,
+	}
+}
+
+func softmax(logits []float64) []float64 {
+	maxLogit := logits[0]
+	for _, l := range logits[1:] {
+		maxLogit = math.Max(maxLogit, l)
+	}
+	probs := make([]float64, len(logits))
+	sum := 0.0
+	for i, l := range logits {
+		probs[i] = math.Exp(l - maxLogit)
+		sum += probs[i]
+	}
+	for i := range probs {
+		probs[i] /= sum
+	}
+	return probs
+}
+
+func (m *NetworkModel) Predict(s State) ([]float64, float64) {
+	input := s.Encode()
+	probs := softmax(m.policy.Predict(input))
+	return maskedPriors(probs, s.LegalMoves()), m.value.Predict(input)[0]
+}
+
+func (m *NetworkModel) Train(examples []Example) {
+	if len(examples) == 0 {
+		return
+	}
+
+	values := make([][]float64, len(examples))
+	inputs := make([][]float64, len(examples))
+	for i, ex := range examples {
+		inputs[i] = ex.Input
+		values[i] = []float64{ex.Value}
+	}
+
+	for epoch := 0; epoch < m.epochs; epoch++ {
+		m.value.TrainBatch(inputs, values, nn.MSE{}, m.valueOpt)
+
+		// Cross-entropy against the visit distribution, with illegal moves
+		// masked out of the softmax so they receive no gradient
+		for _, ex := range examples {
+			logits := m.policy.Predict(ex.Input)
+			probs := maskedPriors(softmax(logits), ex.Legal)
+			grad := make([]float64, len(logits))
+			for _, move := range ex.Legal {
+				grad[move] = probs[move] - ex.Policy[move]
+			}
+			m.policy.Backward(grad)
+		}
+		m.policyOpt.Step(m.policy, len(examples))
+	}
+}
+
+func (m *NetworkModel) Clone() Model {
+	return &NetworkModel{
+		policy:    m.policy.Clone(),
+		value:     m.value.Clone(),
+		policyOpt: nn.NewAdam(0.005),
+		valueOpt:  nn.NewAdam(0.005),
+		epochs:    m.epochs,
+	}
+}
+
+// Save writes the policy and value networks to filename.policy and filename.value
+func (m *NetworkModel) Save(filename string) error {
+	if err := m.policy.Save(filename + ".policy"); err != nil {
+		return err
+	}
+	return m.value.Save(filename + ".value")
+}
+
+func LoadNetworkModel(filename string) (*NetworkModel, error) {
+	policy, err := nn.Load(filename + ".policy")
+	if err != nil {
+		return nil, err
+	}
+	value, err := nn.Load(filename + ".value")
+	if err != nil {
+		return nil, err
+	}
+	return &NetworkModel{
+		policy:    policy,
+		value:     value,
+		policyOpt: nn.NewAdam(0.005),
+		valueOpt:  nn.NewAdam(0.005),
+		epochs:    10,
+	}, nil
+}
@@ -0,0 +1,172 @@
+package alphazero
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+)
+
+// Config controls the self-play training loop
+type Config struct {
+	Iterations   int
+	GamesPerIter int
+	Simulations  int
+	ArenaGames   int
+	PromoteScore float64 // minimum arena score for the candidate to replace the best model
+	ExploreMoves int     // moves per game sampled at temperature 1 before playing greedily
+	ReplayWindow int     // number of recent iterations whose examples are trained on
+	Seed         int64
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Iterations:   20,
+		GamesPerIter: 50,
+		Simulations:  50,
+		ArenaGames:   40,
+		PromoteScore: 0.55,
+		ExploreMoves: 3,
+		ReplayWindow: 4,
+		Seed:         1,
+	}
+}
+
+// Pipeline runs self-play, training and arena gating
+type Pipeline struct {
+	Config  Config
+	Best    Model
+	NewRoot func() State
+	Log     io.Writer
+
+	rng     *rand.Rand
+	history [][]Example
+}
+
+// NewPipeline starts from an initial model; newRoot returns the starting position of a game
+func NewPipeline(config Config, initial Model, newRoot func() State, log io.Writer) *Pipeline {
+	return &Pipeline{
+		Config:  config,
+		Best:    initial,
+		NewRoot: newRoot,
+		Log:     log,
+		rng:     rand.New(rand.NewSource(config.Seed)),
+	}
+}
+
+// Run executes every iteration and returns the number of promotions
+func (p *Pipeline) Run() int {
+	promotions := 0
+	for i := 0; i < p.Config.Iterations; i++ {
+		if p.Iterate(i + 1) {
+			promotions++
+		}
+	}
+	return promotions
+}
+
+// Iterate generates self-play games with the best model, trains a candidate
+// on them and promotes it if it wins the arena match
+func (p *Pipeline) Iterate(iteration int) bool {
+	var examples []Example
+	for g := 0; g < p.Config.GamesPerIter; g++ {
+		examples = append(examples, p.SelfPlay(p.Best)...)
+	}
+
+	p.history = append(p.history, examples)
+	if len(p.history) > p.Config.ReplayWindow {
+		p.history = p.history[1:]
+	}
+	var window []Example
+	for _, batch := range p.history {
+		window = append(window, batch...)
+	}
+
+	candidate := p.Best.Clone()
+	candidate.Train(window)
+
+	score := p.Arena(candidate, p.Best)
+	promoted := score >= p.Config.PromoteScore
+	if promoted {
+		p.Best = candidate
+	}
+
+	if p.Log != nil {
+		fmt.Fprintf(p.Log, "Iteration %d: %d examples, arena score %.2f, promoted %v\n",
+			iteration, len(examples), score, promoted)
+	}
+	return promoted
+}
+
+// SelfPlay plays one game of the model against itself and returns an example
+// per position, with the value target set from that position's mover's view
+func (p *Pipeline) SelfPlay(model Model) []Example {
+	search := NewSearch(model, p.Config.Simulations, p.rng)
+	state := p.NewRoot()
+
+	var examples []Example
+	var movers []int
+	for move := 0; ; move++ {
+		if over, outcome := state.Outcome(); over {
+			// outcome is for the player to move at the final position
+			for i := range examples {
+				if movers[i] == state.ToMove() {
+					examples[i].Value = outcome
+				} else {
+					examples[i].Value = -outcome
+				}
+			}
+			return examples
+		}
+
+		counts := search.VisitCounts(state)
+		examples = append(examples, Example{
+			Key:    state.Key(),
+			Input:  state.Encode(),
+			Legal:  state.LegalMoves(),
+			Policy: Policy(counts, 1),
+		})
+		movers = append(movers, state.ToMove())
+
+		temperature := 1.0
+		if move >= p.Config.ExploreMoves {
+			temperature = 0
+		}
+		state = state.Play(sample(p.rng, Policy(counts, temperature)))
+	}
+}
+
+// Arena plays candidate against best with alternating first moves and returns
+// the candidate's score (win 1, draw 0.5)
+func (p *Pipeline) Arena(candidate, best Model) float64 {
+	score := 0.0
+	for g := 0; g < p.Config.ArenaGames; g++ {
+		players := []*Search{NewSearch(candidate, p.Config.Simulations, p.rng), NewSearch(best, p.Config.Simulations, p.rng)}
+		for _, s := range players {
+			s.NoiseFraction = 0
+		}
+		candidateSeat := g % 2
+
+		state := p.NewRoot()
+		for move := 0; ; move++ {
+			over, outcome := state.Outcome()
+			if over {
+				// The seat to move at the end has the given outcome
+				seatToMove := move % 2
+				if outcome == 0 {
+					score += 0.5
+				} else if (seatToMove == candidateSeat) == (outcome > 0) {
+					score++
+				}
+				break
+			}
+
+			counts := players[(move+candidateSeat)%2].VisitCounts(state)
+			temperature := 0.0
+			if move < p.Config.ExploreMoves {
+				temperature = 1
+			}
+			state = state.Play(sample(p.rng, Policy(counts, temperature)))
+		}
+	}
+	return score / float64(p.Config.ArenaGames)
+}
@@ -0,0 +1,90 @@
+package alphazero
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+func newRoot() State {
+	return NewTicTacToeState(game.NewBoard(), 1)
+}
+
+func TestSearchFindsWin(t *testing.T) {
+	models := []struct {
+		name  string
+		model Model
+	}{
+		{name: "tabular", model: NewTabularModel(9)},
+		{name: "network", model: NewNetworkModel(18, 9, rand.New(rand.NewSource(1)))},
+	}
+
+	b := game.NewBoard()
+	b.SetState([][]int{
+		{1, 1, 0},
+		{2, 2, 0},
+		{0, 0, 0},
+	})
+
+	for _, tt := range models {
+		t.Run(tt.name, func(t *testing.T) {
+			search := NewSearch(tt.model, 200, rand.New(rand.NewSource(1)))
+			search.NoiseFraction = 0
+			counts := search.VisitCounts(NewTicTacToeState(b, 1))
+			best := 0
+			for move, count := range counts {
+				if count > counts[best] {
+					best = move
+				}
+			}
+			if best != 2 {
+				t.Errorf("most visited move = %v, want 2 (visits %v)", best, counts)
+			}
+		})
+	}
+}
+
+func TestSelfPlayExamples(t *testing.T) {
+	p := NewPipeline(DefaultConfig(), NewTabularModel(9), newRoot, nil)
+	examples := p.SelfPlay(p.Best)
+
+	if len(examples) < 5 || len(examples) > 9 {
+		t.Fatalf("SelfPlay() returned %d examples, want 5-9", len(examples))
+	}
+	for i, ex := range examples {
+		sum := 0.0
+		for _, prob := range ex.Policy {
+			sum += prob
+		}
+		if sum < 0.999 || sum > 1.001 {
+			t.Errorf("example %d policy sums to %v, want 1", i, sum)
+		}
+		// Consecutive positions belong to opposite players
+		if i > 0 && ex.Value != -examples[i-1].Value {
+			t.Errorf("example %d value = %v, want %v", i, ex.Value, -examples[i-1].Value)
+		}
+	}
+}
+
+func TestPipelineRun(t *testing.T) {
+	config := DefaultConfig()
+	config.Iterations = 3
+	config.GamesPerIter = 20
+	config.ArenaGames = 10
+	config.Simulations = 25
+
+	p := NewPipeline(config, NewTabularModel(9), newRoot, nil)
+	p.Run()
+
+	player := NewAgent(1, p.Best, 50, 1)
+	b := game.NewBoard()
+	b.SetState([][]int{
+		{2, 2, 0},
+		{1, 1, 0},
+		{1, 0, 0},
+	})
+	if move := player.GetMove(b, 1); move != 2 && move != 5 {
+		t.Errorf("GetMove() = %v, want a winning move (2 or 5)", move)
+	}
+}
@@ -0,0 +1,27 @@
+package alphazero
+
+import "github.com/jpotts18/tictactoe/agent"
+
+// DefaultSimulations is the search budget of a registered AlphaZero agent
+const DefaultSimulations = 50
+
+func init() {
+	newAgent := func(player int, model Model, params *agent.Params) *Agent {
+		return NewAgent(player, model, params.Int("simulations", DefaultSimulations), params.Int64("seed", 1))
+	}
+	agent.Register(agent.Registration{
+		Name:      "alphazero",
+		Title:     "AlphaZero",
+		Extension: ".alphazero",
+		New: func(player int, params *agent.Params) (agent.Agent, error) {
+			return newAgent(player, NewTabularModel(9), params), nil
+		},
+		Load: func(player int, filename string, params *agent.Params) (agent.Agent, error) {
+			model, err := LoadTabularModel(filename, 9)
+			if err != nil {
+				return nil, err
+			}
+			return newAgent(player, model, params), nil
+		},
+	})
+}
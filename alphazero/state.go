@@ -0,0 +1,105 @@
+// Package alphazero implements an AlphaZero-style self-play pipeline: a
+// policy/value model guides PUCT tree search, search visit counts become
+// policy targets, game outcomes become value targets, and each new model must
+// beat the previous best in an arena match before it is promoted.
+//
+// The search and training loop only see the State interface, so a bigger game
+// can be plugged in by implementing State for it.
+package alphazero
+
+import (
+	"strconv"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+// State is a game position as seen by the search
+type State interface {
+	// ActionSize is the number of distinct actions in the game
+	ActionSize() int
+
+	// LegalMoves returns the actions available to the player to move
+	LegalMoves() []int
+
+	// Play returns the state after the player to move takes the action
+	Play(action int) State
+
+	// Outcome reports whether the game is over and, if so, the result from the
+	// perspective of the player to move: 1 win, 0 draw, -1 loss
+	Outcome() (bool, float64)
+
+	// ToMove returns the player whose turn it is
+	ToMove() int
+
+	// Key uniquely identifies the position, including the player to move
+	Key() string
+
+	// Encode returns the network input from the perspective of the player to move
+	Encode() []float64
+}
+
+// TicTacToeState adapts game.Board to State
+type TicTacToeState struct {
+	board  *game.Board
+	toMove int
+}
+
+// NewTicTacToeState wraps a board with the player who is about to move
+func NewTicTacToeState(board *game.Board, toMove int) *TicTacToeState {
+	copied := *board
+	return &TicTacToeState{board: &copied, toMove: toMove}
+}
+
+func (s *TicTacToeState) ActionSize() int {
+	return 9
+}
+
+func (s *TicTacToeState) LegalMoves() []int {
+	if over, _ := s.board.IsGameOver(); over {
+		return nil
+	}
+	return s.board.GetAvailableMoves()
+}
+
+func (s *TicTacToeState) Play(action int) State {
+	next := NewTicTacToeState(s.board, s.toMove%2+1)
+	next.board.MakeMove(action, s.toMove)
+	return next
+}
+
+func (s *TicTacToeState) Outcome() (bool, float64) {
+	over, winner := s.board.IsGameOver()
+	if !over || winner == 0 {
+		return over, 0
+	}
+	if winner == s.toMove {
+		return true, 1
+	}
+	return true, -1
+}
+
+func (s *TicTacToeState) ToMove() int {
+	return s.toMove
+}
+
+func (s *TicTacToeState) Key() string {
+	key := strconv.Itoa(s.toMove)
+	for i := 0; i < 9; i++ {
+		key += strconv.Itoa(s.board.GetCell(i))
+	}
+	return key
+}
+
+func (s *TicTacToeState) Encode() []float64 {
+	input := make([]float64, 18)
+	for i := 0; i < 9; i++ {
+		switch s.board.GetCell(i) {
+		case 0:
+		case s.toMove:
+			input[i] = 1
+		default:
+			input[9+i] = 1
+		}
+	}
+	return input
+}
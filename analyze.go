@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jpotts18/tictactoe/agent"
+	"github.com/jpotts18/tictactoe/game"
+	"github.com/jpotts18/tictactoe/solver"
+)
+
+// qValuer is implemented by agents that keep a Q-value per move
+type qValuer interface {
+	agent.Agent
+	GetQValues(state string) []float64
+}
+
+// analysisModel is a trained model whose Q-values are shown next to the solver's values
+type analysisModel struct {
+	name  string
+	agent qValuer
+}
+
+// loadAnalysisModels loads every saved model in models/ that exposes Q-values,
+// seated as player
+func loadAnalysisModels(player int) []analysisModel {
+	var models []analysisModel
+	for _, r := range agent.Registered() {
+		if r.Extension == "" {
+			continue
+		}
+		files, _ := filepath.Glob(filepath.Join("models", "*"+r.Extension))
+		sort.Strings(files)
+		for _, file := range files {
+			a, err := r.Open(player, file, agent.NewParams(nil))
+			if err != nil {
+				continue
+			}
+			valuer, ok := a.(qValuer)
+			if !ok {
+				break
+			}
+			models = append(models, analysisModel{name: filepath.Base(file), agent: valuer})
+		}
+	}
+	return models
+}
+
+// analysisTable lists every legal move with its perfect-play value and each
+// model's Q-value for the position
+func analysisTable(board *game.Board, player int, models []analysisModel) string {
+	tb := solver.Default()
+	best, ok := tb.Lookup(board, player)
+	if !ok {
+		best = solver.Solve(board, player)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s to move: %s\n\n", markName(player), best)
+
+	fmt.Fprintf(&sb, "%-6s %-10s", "Move", "Value")
+	for _, m := range models {
+		fmt.Fprintf(&sb, " %14s", m.name)
+	}
+	sb.WriteString("\n")
+
+	qValues := make([][]float64, len(models))
+	for i, m := range models {
+		qValues[i] = m.agent.GetQValues(m.agent.GetStateKey(board))
+	}
+	for _, mv := range tb.MoveValues(board, player) {
+		marker := " "
+		if mv.Value == best {
+			marker = "*"
+		}
+		fmt.Fprintf(&sb, "%s%-5d %-10s", marker, mv.Move+1, mv.Value)
+		for i := range models {
+			fmt.Fprintf(&sb, " %14.3f", qValues[i][mv.Move])
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// analyzePosition prints the analysis table for a state string or ASCII grid
+func analyzePosition(input string) {
+	board, err := game.ParseBoard(input)
+	if err != nil {
+		fmt.Println("Invalid position:", err)
+		return
+	}
+	if over, winner := board.IsGameOver(); over {
+		fmt.Printf("%s\n\nThe game is over (winner %d)\n", board.String(), winner)
+		return
+	}
+
+	player := solver.PlayerToMove(board)
+	fmt.Printf("%s\n\n", board.String())
+	fmt.Print(analysisTable(board, player, loadAnalysisModels(player)))
+}
@@ -0,0 +1,276 @@
+// Package config describes a training experiment in a JSON file: the agents
+// and their hyperparameters, how they train and are evaluated, the reward
+// scheme, the seed and where the results go. Fields left out of the file keep
+// the values of DefaultExperiment, which reproduces plain -train.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/jpotts18/tictactoe/agent"
+	"github.com/jpotts18/tictactoe/reward"
+	"github.com/jpotts18/tictactoe/training"
+)
+
+// Agent is one agent to train. Options and Explore apply to the tabular
+// agents only; options left out of the file keep agent.DefaultOptions.
+type Agent struct {
+	Name    string         `json:"name,omitempty"` // file name for the model and metrics, defaults to Type
+	Type    string         `json:"type"`           // a registered learning agent, e.g. qlearning or dqn
+	Options *agent.Options `json:"options,omitempty"`
+	Explore string         `json:"explore,omitempty"` // an agent.ParseExplorer spec
+}
+
+// UnmarshalJSON fills the options the file leaves out from the defaults
+func (a *Agent) UnmarshalJSON(data []byte) error {
+	type plain Agent
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	if p.Options != nil {
+		var raw struct {
+			Options json.RawMessage `json:"options"`
+		}
+		opts := agent.DefaultOptions()
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(raw.Options, &opts); err != nil {
+			return err
+		}
+		p.Options = &opts
+	}
+	*a = Agent(p)
+	return nil
+}
+
+// tabular reports whether the agent type takes Options and an explorer
+func (a Agent) tabular() bool {
+	switch a.Type {
+	case "qlearning", "sarsa", "montecarlo":
+		return true
+	}
+	return false
+}
+
+// New builds the agent as player 1 through the agent registry. seed is used
+// by agents with their own random source.
+func (a Agent) New(seed int64) (agent.LearningAgent, error) {
+	params := agent.NewParams(nil)
+	if a.Options != nil {
+		params = a.Options.Params()
+	}
+	if a.Type == "dqn" {
+		params.Set("seed", strconv.FormatInt(seed, 10))
+	}
+	built, err := agent.NewWithParams(a.Type, 1, params)
+	if err != nil {
+		return nil, err
+	}
+	learner, ok := built.(agent.LearningAgent)
+	if !ok {
+		return nil, fmt.Errorf("agent %s does not learn", a.Type)
+	}
+	if a.Explore != "" {
+		exploring, ok := learner.(agent.Exploring)
+		if !ok {
+			return nil, fmt.Errorf("agent %s does not take an explorer", a.Type)
+		}
+		explorer, err := agent.ParseExplorer(a.Explore)
+		if err != nil {
+			return nil, err
+		}
+		exploring.SetExplorer(explorer)
+	}
+	if seeder, ok := learner.(agent.Seeder); ok {
+		seeder.Seed(seed)
+	}
+	return learner, nil
+}
+
+// Training sets how the agents learn. Opponent is "selfplay" or an
+// agent.New spec; a curriculum replaces it.
+type Training struct {
+	Games      int                  `json:"games"`
+	Opponent   string               `json:"opponent,omitempty"`
+	Curriculum *training.Curriculum `json:"curriculum,omitempty"`
+}
+
+// Schedule returns the curriculum the agents train through, or nil for
+// self-play. A fixed opponent is a curriculum of one stage.
+func (t Training) Schedule() *training.Curriculum {
+	if t.Curriculum != nil {
+		return t.Curriculum
+	}
+	if t.Opponent == "" || t.Opponent == "selfplay" {
+		return nil
+	}
+	return &training.Curriculum{Stages: []training.Stage{{Opponent: t.Opponent, Window: 1}}}
+}
+
+// Evaluation sets when the agents are measured and against whom
+type Evaluation struct {
+	Every     int      `json:"every"`     // training games between measurements
+	Opponents []string `json:"opponents"` // agent.New specs
+}
+
+// Rewards is a reward scheme, optionally shaped by reward.Threats
+type Rewards struct {
+	reward.Scheme
+	Threats float64 `json:"threats,omitempty"` // weight of the threat potential, 0 for none
+	Gamma   float64 `json:"gamma,omitempty"`   // discount used by the shaping term
+}
+
+// Function returns the reward function the agents learn from
+func (r Rewards) Function() reward.Function {
+	if r.Threats == 0 {
+		return r.Scheme
+	}
+	return reward.Shaped{Base: r.Scheme, Potential: reward.Threats(r.Threats), Gamma: r.Gamma}
+}
+
+// Experiment is a complete training run
+type Experiment struct {
+	Name       string     `json:"name"`
+	Game       string     `json:"game"`
+	Seed       int64      `json:"seed"`             // 0 picks one from the clock
+	Output     string     `json:"output,omitempty"` // defaults to runs/<start time>-<name>
+	Agents     []Agent    `json:"agents"`
+	Training   Training   `json:"training"`
+	Evaluation Evaluation `json:"evaluation"`
+	Rewards    Rewards    `json:"rewards"`
+}
+
+// DefaultExperiment trains every learning agent by self-play for 100000
+// games, measuring them every 5000 against the random and minimax agents
+func DefaultExperiment() Experiment {
+	return Experiment{
+		Name: "experiment",
+		Game: "tictactoe",
+		Agents: []Agent{
+			{Type: "qlearning"},
+			{Type: "sarsa"},
+			{Type: "montecarlo"},
+			{Type: "actorcritic"},
+			{Type: "dqn"},
+			{Type: "menace"},
+		},
+		Training:   Training{Games: 100000, Opponent: "selfplay"},
+		Evaluation: Evaluation{Every: 5000, Opponents: []string{"random", "minimax"}},
+		Rewards: Rewards{
+			Scheme: reward.Scheme{Win: 1.0, Draw: 0.5, Loss: -2.0, Step: -0.01},
+			Gamma:  agent.DefaultOptions().Gamma,
+		},
+	}
+}
+
+// Load reads an experiment file over DefaultExperiment and validates it
+func Load(filename string) (Experiment, error) {
+	e := DefaultExperiment()
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return e, err
+	}
+	if err := json.Unmarshal(data, &e); err != nil {
+		return e, fmt.Errorf("%s: %v", filename, err)
+	}
+	if err := e.Validate(); err != nil {
+		return e, fmt.Errorf("%s: %v", filename, err)
+	}
+	return e, nil
+}
+
+// Validate checks every part of the experiment
+func (e Experiment) Validate() error {
+	if e.Game != "tictactoe" {
+		return fmt.Errorf("unknown game %q, only tictactoe is supported", e.Game)
+	}
+	if len(e.Agents) == 0 {
+		return fmt.Errorf("no agents to train")
+	}
+	names := make(map[string]bool)
+	for _, a := range e.Agents {
+		if _, err := a.New(e.Seed); err != nil {
+			return err
+		}
+		if a.Options != nil {
+			if err := a.Options.Validate(); err != nil {
+				return fmt.Errorf("agent %s: %v", a.Type, err)
+			}
+		}
+		name := a.Name
+		if name == "" {
+			name = a.Type
+		}
+		if names[name] {
+			return fmt.Errorf("two agents are named %q; give them distinct names", name)
+		}
+		names[name] = true
+	}
+
+	if e.Training.Games <= 0 {
+		return fmt.Errorf("training games must be positive, got %d", e.Training.Games)
+	}
+	if e.Training.Curriculum != nil && e.Training.Opponent != "" && e.Training.Opponent != "selfplay" {
+		return fmt.Errorf("training has both an opponent and a curriculum")
+	}
+	if c := e.Training.Schedule(); c != nil {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+	if e.Evaluation.Every <= 0 {
+		return fmt.Errorf("evaluation interval must be positive, got %d", e.Evaluation.Every)
+	}
+	for _, spec := range e.Evaluation.Opponents {
+		if _, err := agent.New(spec, 2); err != nil {
+			return err
+		}
+	}
+	if e.Rewards.Gamma < 0 || e.Rewards.Gamma > 1 {
+		return fmt.Errorf("rewards gamma %g is outside [0, 1]", e.Rewards.Gamma)
+	}
+	return nil
+}
+
+// Resolve fills in everything chosen at run time: the seed if it is 0, the
+// output directory, agent names and the default options of tabular agents.
+// The resolved experiment reruns the same way.
+func (e *Experiment) Resolve(start time.Time) {
+	if e.Seed == 0 {
+		e.Seed = start.UnixNano()
+	}
+	if e.Output == "" {
+		e.Output = filepath.Join("runs", start.Format("20060102-150405")+"-"+e.Name)
+	}
+	for i := range e.Agents {
+		a := &e.Agents[i]
+		if a.Name == "" {
+			a.Name = a.Type
+		}
+		if a.tabular() && a.Options == nil {
+			opts := agent.DefaultOptions()
+			a.Options = &opts
+		}
+	}
+	if e.Training.Curriculum != nil {
+		e.Training.Opponent = ""
+	} else if e.Training.Opponent == "" {
+		e.Training.Opponent = "selfplay"
+	}
+}
+
+// Save writes the experiment as indented JSON
+func (e Experiment) Save(filename string) error {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jpotts18/tictactoe/agent"
+	"github.com/jpotts18/tictactoe/reward"
+	"github.com/jpotts18/tictactoe/training"
+)
+
+func writeFile(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "experiment.json")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadKeepsDefaults(t *testing.T) {
+	path := writeFile(t, `{
+		"name": "fast-q",
+		"agents": [
+			{"type": "qlearning", "options": {"alpha": 0.3}, "explore": "boltzmann"},
+			{"name": "sarsa-slow", "type": "sarsa", "options": {"decay": 0.9999}}
+		],
+		"training": {"games": 2000},
+		"rewards": {"loss": -1}
+	}`)
+	e, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := agent.DefaultOptions()
+	q.Alpha = 0.3
+	sarsa := agent.DefaultOptions()
+	sarsa.Decay = 0.9999
+	wantAgents := []Agent{
+		{Type: "qlearning", Options: &q, Explore: "boltzmann"},
+		{Name: "sarsa-slow", Type: "sarsa", Options: &sarsa},
+	}
+	if !reflect.DeepEqual(e.Agents, wantAgents) {
+		t.Errorf("Agents = %+v, want %+v", e.Agents, wantAgents)
+	}
+
+	defaults := DefaultExperiment()
+	if e.Training.Games != 2000 || e.Training.Opponent != "selfplay" {
+		t.Errorf("Training = %+v, want 2000 self-play games", e.Training)
+	}
+	if !reflect.DeepEqual(e.Evaluation, defaults.Evaluation) {
+		t.Errorf("Evaluation = %+v, want the defaults %+v", e.Evaluation, defaults.Evaluation)
+	}
+	wantRewards := defaults.Rewards
+	wantRewards.Loss = -1
+	if e.Rewards != wantRewards {
+		t.Errorf("Rewards = %+v, want %+v", e.Rewards, wantRewards)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(e *Experiment)
+	}{
+		{"other game", func(e *Experiment) { e.Game = "connect4" }},
+		{"no agents", func(e *Experiment) { e.Agents = nil }},
+		{"unknown agent", func(e *Experiment) { e.Agents = []Agent{{Type: "alphago"}} }},
+		{"options on a non-tabular agent", func(e *Experiment) {
+			opts := agent.DefaultOptions()
+			e.Agents = []Agent{{Type: "dqn", Options: &opts}}
+		}},
+		{"explorer on a non-tabular agent", func(e *Experiment) { e.Agents = []Agent{{Type: "menace", Explore: "ucb"}} }},
+		{"bad explorer", func(e *Experiment) { e.Agents = []Agent{{Type: "qlearning", Explore: "greedyish"}} }},
+		{"bad options", func(e *Experiment) {
+			opts := agent.DefaultOptions()
+			opts.Alpha = 2
+			e.Agents = []Agent{{Type: "qlearning", Options: &opts}}
+		}},
+		{"duplicate names", func(e *Experiment) { e.Agents = []Agent{{Type: "sarsa"}, {Type: "sarsa"}} }},
+		{"no games", func(e *Experiment) { e.Training.Games = 0 }},
+		{"unknown opponent", func(e *Experiment) { e.Training.Opponent = "grandmaster" }},
+		{"opponent and curriculum", func(e *Experiment) {
+			c := training.DefaultCurriculum()
+			e.Training.Opponent, e.Training.Curriculum = "random", &c
+		}},
+		{"no evaluation interval", func(e *Experiment) { e.Evaluation.Every = 0 }},
+		{"unknown benchmark", func(e *Experiment) { e.Evaluation.Opponents = []string{"minimax:width=2"} }},
+	}
+	if err := DefaultExperiment().Validate(); err != nil {
+		t.Fatalf("DefaultExperiment() is invalid: %v", err)
+	}
+	for _, tt := range tests {
+		e := DefaultExperiment()
+		tt.edit(&e)
+		if err := e.Validate(); err == nil {
+			t.Errorf("%s: Validate() succeeded, want an error", tt.name)
+		}
+	}
+}
+
+func TestResolveRoundTrip(t *testing.T) {
+	e := DefaultExperiment()
+	e.Name = "baseline"
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	e.Resolve(start)
+
+	if e.Seed != start.UnixNano() {
+		t.Errorf("Seed = %d, want one taken from the start time", e.Seed)
+	}
+	if want := filepath.Join("runs", "20260102-030405-baseline"); e.Output != want {
+		t.Errorf("Output = %q, want %q", e.Output, want)
+	}
+	for _, a := range e.Agents {
+		if a.Name != a.Type {
+			t.Errorf("agent %s resolved to name %q", a.Type, a.Name)
+		}
+		if (a.Options != nil) != a.tabular() {
+			t.Errorf("agent %s resolved options %v", a.Type, a.Options)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "experiment.json")
+	if err := e.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, e) {
+		t.Errorf("Load(Save(e)) = %+v, want %+v", loaded, e)
+	}
+
+	// A seed given in the file is kept
+	e.Seed = 7
+	e.Resolve(start)
+	if e.Seed != 7 {
+		t.Errorf("Resolve() replaced seed 7 with %d", e.Seed)
+	}
+}
+
+func TestTrainingSchedule(t *testing.T) {
+	if c := (Training{Opponent: "selfplay"}).Schedule(); c != nil {
+		t.Errorf("Schedule() for self-play = %+v, want nil", c)
+	}
+	want := &training.Curriculum{Stages: []training.Stage{{Opponent: "heuristic", Window: 1}}}
+	if c := (Training{Opponent: "heuristic"}).Schedule(); !reflect.DeepEqual(c, want) {
+		t.Errorf("Schedule() for a fixed opponent = %+v, want %+v", c, want)
+	}
+	c := training.DefaultCurriculum()
+	if got := (Training{Curriculum: &c}).Schedule(); got != &c {
+		t.Errorf("Schedule() = %p, want the curriculum %p", got, &c)
+	}
+}
+
+func TestRewardsFunction(t *testing.T) {
+	r := DefaultExperiment().Rewards
+	if _, ok := r.Function().(reward.Scheme); !ok {
+		t.Errorf("Function() = %T without threats, want reward.Scheme", r.Function())
+	}
+	r.Threats = 0.1
+	if _, ok := r.Function().(reward.Shaped); !ok {
+		t.Errorf("Function() = %T with threats, want reward.Shaped", r.Function())
+	}
+}
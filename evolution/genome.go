@@ -0,0 +1,243 @@
+package evolution
+
+import (
+	"encoding/json"
+	"math/rand"
+	"os"
+
+	"github.com/jpotts18/tictactoe/agent"
+	"github.com/jpotts18/tictactoe/game"
+	"github.com/jpotts18/tictactoe/nn"
+)
+
+// Genome is an evolvable policy
+type Genome interface {
+	// Agent returns a player backed by this genome
+	Agent(player int) agent.Agent
+
+	// Mutate perturbs each gene with probability rate by Gaussian noise of the given scale
+	Mutate(rng *rand.Rand, rate, scale float64)
+
+	// Crossover mixes this genome with another of the same kind
+	Crossover(other Genome, rng *rand.Rand) Genome
+
+	Clone() Genome
+	Save(filename string) error
+}
+
+// PolicyAgent plays the legal move with the highest score from its policy
+type PolicyAgent struct {
+	agent.BaseAgent
+	scores func(board *game.Board, player int) []float64
+}
+
+func (p *PolicyAgent) GetMove(board *game.Board, player int) int {
+	moves := board.GetAvailableMoves()
+	if len(moves) == 0 {
+		return -1
+	}
+	scores := p.scores(board, player)
+	bestMove := moves[0]
+	for _, move := range moves[1:] {
+		if scores[move] > scores[bestMove] {
+			bestMove = move
+		}
+	}
+	return bestMove
+}
+
+// Learn is a no-op; policies only change between generations
+func (p *PolicyAgent) Learn(oldState string, action int, reward float64, newState string) {
+}
+
+// TableGenome is a lookup table of move preferences per state. States are
+// added with random preferences the first time the policy meets them. States
+// are keyed from the mover's side when Relative is set, so one table serves
+// both seats; tables saved before it existed are absolute.
+type TableGenome struct {
+	Preferences map[string][]float64 `json:"preferences"`
+	Relative    bool                 `json:"relative"`
+	rng         *rand.Rand
+}
+
+func NewTableGenome(rng *rand.Rand) *TableGenome {
+	return &TableGenome{
+		Preferences: make(map[string][]float64),
+		Relative:    true,
+		rng:         rand.New(rand.NewSource(rng.Int63())),
+	}
+}
+
+func (g *TableGenome) Agent(player int) agent.Agent {
+	p := &PolicyAgent{BaseAgent: agent.BaseAgent{Player: player, Relative: g.Relative}}
+	p.scores = func(board *game.Board, player int) []float64 {
+		state := p.GetStateKey(board)
+		prefs, exists := g.Preferences[state]
+		if !exists {
+			prefs = make([]float64, 9)
+			for i := range prefs {
+				prefs[i] = g.rng.NormFloat64()
+			}
+			g.Preferences[state] = prefs
+		}
+		return prefs
+	}
+	return p
+}
+
+func (g *TableGenome) Mutate(rng *rand.Rand, rate, scale float64) {
+	for _, prefs := range g.Preferences {
+		for i := range prefs {
+			if rng.Float64() < rate {
+				prefs[i] += rng.NormFloat64() * scale
+			}
+		}
+	}
+}
+
+// Crossover takes each state's preferences from one parent or the other
+func (g *TableGenome) Crossover(other Genome, rng *rand.Rand) Genome {
+	mate := other.(*TableGenome)
+	child := NewTableGenome(rng)
+	child.Relative = g.Relative
+	for state, prefs := range g.Preferences {
+		child.Preferences[state] = append([]float64(nil), prefs...)
+	}
+	for state, prefs := range mate.Preferences {
+		if _, exists := child.Preferences[state]; !exists || rng.Float64() < 0.5 {
+			child.Preferences[state] = append([]float64(nil), prefs...)
+		}
+	}
+	return child
+}
+
+func (g *TableGenome) Clone() Genome {
+	clone := NewTableGenome(g.rng)
+	clone.Relative = g.Relative
+	for state, prefs := range g.Preferences {
+		clone.Preferences[state] = append([]float64(nil), prefs...)
+	}
+	return clone
+}
+
+func (g *TableGenome) Save(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return json.NewEncoder(file).Encode(g)
+}
+
+// LoadTableAgent loads a saved TableGenome as a fixed player. States missing
+// from the table fall back to the first legal move.
+func LoadTableAgent(filename string, player int) (*PolicyAgent, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var g TableGenome
+	if err := json.NewDecoder(file).Decode(&g); err != nil {
+		return nil, err
+	}
+
+	p := &PolicyAgent{BaseAgent: agent.BaseAgent{Player: player, Relative: g.Relative}}
+	p.scores = func(board *game.Board, player int) []float64 {
+		if prefs, exists := g.Preferences[p.GetStateKey(board)]; exists {
+			return prefs
+		}
+		return make([]float64, 9)
+	}
+	return p, nil
+}
+
+// NetworkGenome is the weights of a small nn.Network mapping the board, seen
+// from the mover's side, to a score per cell
+type NetworkGenome struct {
+	Net *nn.Network
+}
+
+func NewNetworkGenome(rng *rand.Rand) *NetworkGenome {
+	return &NetworkGenome{Net: nn.NewNetwork([]int{18, 16, 9}, nn.Tanh, nn.Linear, rng)}
+}
+
+func encodeBoard(board *game.Board, player int) []float64 {
+	input := make([]float64, 18)
+	for i := 0; i < 9; i++ {
+		switch board.GetCell(i) {
+		case 0:
+		case player:
+			input[i] = 1
+		default:
+			input[9+i] = 1
+		}
+	}
+	return input
+}
+
+func (g *NetworkGenome) Agent(player int) agent.Agent {
+	return networkAgent(g.Net, player)
+}
+
+func networkAgent(net *nn.Network, player int) *PolicyAgent {
+	return &PolicyAgent{
+		BaseAgent: agent.BaseAgent{Player: player},
+		scores: func(board *game.Board, player int) []float64 {
+			return net.Predict(encodeBoard(board, player))
+		},
+	}
+}
+
+func (g *NetworkGenome) Mutate(rng *rand.Rand, rate, scale float64) {
+	for _, layer := range g.Net.Layers {
+		for o := range layer.Weights {
+			for i := range layer.Weights[o] {
+				if rng.Float64() < rate {
+					layer.Weights[o][i] += rng.NormFloat64() * scale
+				}
+			}
+			if rng.Float64() < rate {
+				layer.Biases[o] += rng.NormFloat64() * scale
+			}
+		}
+	}
+}
+
+// Crossover picks each weight uniformly from one parent or the other
+func (g *NetworkGenome) Crossover(other Genome, rng *rand.Rand) Genome {
+	mate := other.(*NetworkGenome)
+	child := &NetworkGenome{Net: g.Net.Clone()}
+	for l, layer := range child.Net.Layers {
+		for o := range layer.Weights {
+			for i := range layer.Weights[o] {
+				if rng.Float64() < 0.5 {
+					layer.Weights[o][i] = mate.Net.Layers[l].Weights[o][i]
+				}
+			}
+			if rng.Float64() < 0.5 {
+				layer.Biases[o] = mate.Net.Layers[l].Biases[o]
+			}
+		}
+	}
+	return child
+}
+
+func (g *NetworkGenome) Clone() Genome {
+	return &NetworkGenome{Net: g.Net.Clone()}
+}
+
+func (g *NetworkGenome) Save(filename string) error {
+	return g.Net.Save(filename)
+}
+
+// LoadNetworkAgent loads a saved NetworkGenome as a fixed player
+func LoadNetworkAgent(filename string, player int) (*PolicyAgent, error) {
+	net, err := nn.Load(filename)
+	if err != nil {
+		return nil, err
+	}
+	return networkAgent(net, player), nil
+}
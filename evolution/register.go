@@ -0,0 +1,23 @@
+package evolution
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/jpotts18/tictactoe/agent"
+)
+
+func init() {
+	agent.Register(agent.Registration{
+		Name:      "evolved",
+		Title:     "Evolved",
+		Extension: ".table",
+		New: func(player int, params *agent.Params) (agent.Agent, error) {
+			rng := rand.New(rand.NewSource(params.Int64("seed", time.Now().UnixNano())))
+			return NewTableGenome(rng).Agent(player), nil
+		},
+		Load: func(player int, filename string, params *agent.Params) (agent.Agent, error) {
+			return LoadTableAgent(filename, player)
+		},
+	})
+}
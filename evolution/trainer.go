@@ -0,0 +1,190 @@
+// Package evolution searches for tic-tac-toe policies with a genetic
+// algorithm: tournament selection, crossover and mutation over lookup-table
+// or small-network genomes.
+package evolution
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/jpotts18/tictactoe/agent"
+)
+
+// Config controls population size and the genetic operators
+type Config struct {
+	PopulationSize int
+	Generations    int
+	TournamentSize int
+	CrossoverRate  float64
+	MutationRate   float64
+	MutationScale  float64
+	Elites         int // best individuals copied unchanged into the next generation
+	Workers        int
+	Seed           int64
+}
+
+func DefaultConfig() Config {
+	return Config{
+		PopulationSize: 50,
+		Generations:    30,
+		TournamentSize: 3,
+		CrossoverRate:  0.7,
+		MutationRate:   0.1,
+		MutationScale:  0.5,
+		Elites:         2,
+		Workers:        4,
+		Seed:           1,
+	}
+}
+
+// GenerationStats summarizes the fitness of one generation
+type GenerationStats struct {
+	Generation int
+	Best       float64
+	Mean       float64
+	Worst      float64
+	StdDev     float64
+}
+
+// Trainer evolves a population of genomes. Fitness is called concurrently
+// from several goroutines and must be safe for that.
+type Trainer struct {
+	Config    Config
+	NewGenome func(rng *rand.Rand) Genome
+	Fitness   func(a agent.Agent) float64
+	Log       io.Writer
+
+	rng *rand.Rand
+}
+
+func NewTrainer(config Config, newGenome func(rng *rand.Rand) Genome, fitness func(a agent.Agent) float64, log io.Writer) *Trainer {
+	return &Trainer{
+		Config:    config,
+		NewGenome: newGenome,
+		Fitness:   fitness,
+		Log:       log,
+		rng:       rand.New(rand.NewSource(config.Seed)),
+	}
+}
+
+type individual struct {
+	genome  Genome
+	fitness float64
+}
+
+// Run evolves the population and returns the best genome seen along with
+// per-generation statistics
+func (t *Trainer) Run() (Genome, []GenerationStats) {
+	population := make([]individual, t.Config.PopulationSize)
+	for i := range population {
+		population[i].genome = t.NewGenome(t.rng)
+	}
+
+	var best individual
+	best.fitness = math.Inf(-1)
+	var history []GenerationStats
+
+	for gen := 1; gen <= t.Config.Generations; gen++ {
+		t.evaluate(population)
+		sort.SliceStable(population, func(i, j int) bool {
+			return population[i].fitness > population[j].fitness
+		})
+		if population[0].fitness > best.fitness {
+			best = individual{genome: population[0].genome.Clone(), fitness: population[0].fitness}
+		}
+
+		stats := summarize(gen, population)
+		history = append(history, stats)
+		if t.Log != nil {
+			fmt.Fprintf(t.Log, "Generation %d: best %.3f, mean %.3f, worst %.3f, stddev %.3f\n",
+				stats.Generation, stats.Best, stats.Mean, stats.Worst, stats.StdDev)
+		}
+
+		if gen < t.Config.Generations {
+			population = t.breed(population)
+		}
+	}
+	return best.genome, history
+}
+
+// evaluate computes every individual's fitness using a pool of workers
+func (t *Trainer) evaluate(population []individual) {
+	workers := t.Config.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				population[i].fitness = t.Fitness(population[i].genome.Agent(1))
+			}
+		}()
+	}
+	for i := range population {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// breed builds the next generation from a population sorted by fitness
+func (t *Trainer) breed(population []individual) []individual {
+	next := make([]individual, 0, len(population))
+	for i := 0; i < t.Config.Elites && i < len(population); i++ {
+		next = append(next, individual{genome: population[i].genome.Clone()})
+	}
+
+	for len(next) < len(population) {
+		parent := t.tournament(population)
+		var child Genome
+		if t.rng.Float64() < t.Config.CrossoverRate {
+			child = parent.Crossover(t.tournament(population), t.rng)
+		} else {
+			child = parent.Clone()
+		}
+		child.Mutate(t.rng, t.Config.MutationRate, t.Config.MutationScale)
+		next = append(next, individual{genome: child})
+	}
+	return next
+}
+
+// tournament returns the fittest of TournamentSize randomly drawn individuals
+func (t *Trainer) tournament(population []individual) Genome {
+	best := population[t.rng.Intn(len(population))]
+	for i := 1; i < t.Config.TournamentSize; i++ {
+		contender := population[t.rng.Intn(len(population))]
+		if contender.fitness > best.fitness {
+			best = contender
+		}
+	}
+	return best.genome
+}
+
+func summarize(generation int, population []individual) GenerationStats {
+	stats := GenerationStats{
+		Generation: generation,
+		Best:       math.Inf(-1),
+		Worst:      math.Inf(1),
+	}
+	for _, ind := range population {
+		stats.Mean += ind.fitness
+		stats.Best = math.Max(stats.Best, ind.fitness)
+		stats.Worst = math.Min(stats.Worst, ind.fitness)
+	}
+	stats.Mean /= float64(len(population))
+	for _, ind := range population {
+		diff := ind.fitness - stats.Mean
+		stats.StdDev += diff * diff
+	}
+	stats.StdDev = math.Sqrt(stats.StdDev / float64(len(population)))
+	return stats
+}
@@ -0,0 +1,114 @@
+package evolution
+
+import (
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/jpotts18/tictactoe/agent"
+	"github.com/jpotts18/tictactoe/game"
+)
+
+// centerFitness rewards policies that open in the center and answer a corner
+// opening with the center
+func centerFitness(a agent.Agent) float64 {
+	fitness := 0.0
+	if a.GetMove(game.NewBoard(), 1) == 4 {
+		fitness++
+	}
+	b := game.NewBoard()
+	b.MakeMove(0, 2)
+	if a.GetMove(b, 1) == 4 {
+		fitness++
+	}
+	return fitness
+}
+
+func TestTrainerRun(t *testing.T) {
+	genomes := []struct {
+		name      string
+		newGenome func(rng *rand.Rand) Genome
+	}{
+		{name: "table", newGenome: func(rng *rand.Rand) Genome { return NewTableGenome(rng) }},
+		{name: "network", newGenome: func(rng *rand.Rand) Genome { return NewNetworkGenome(rng) }},
+	}
+
+	for _, tt := range genomes {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.PopulationSize = 20
+			config.Generations = 15
+
+			best, history := NewTrainer(config, tt.newGenome, centerFitness, nil).Run()
+
+			if len(history) != config.Generations {
+				t.Fatalf("Run() returned %d generations of stats, want %d", len(history), config.Generations)
+			}
+			if got := centerFitness(best.Agent(1)); got != 2 {
+				t.Errorf("best genome fitness = %v, want 2", got)
+			}
+			for _, stats := range history {
+				if stats.Worst > stats.Mean || stats.Mean > stats.Best {
+					t.Errorf("generation %d stats out of order: %+v", stats.Generation, stats)
+				}
+			}
+		})
+	}
+}
+
+func TestSaveLoadAgent(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	dir := t.TempDir()
+
+	table := NewTableGenome(rng)
+	tableAgent := table.Agent(1)
+	tableMove := tableAgent.GetMove(game.NewBoard(), 1)
+	tableFile := filepath.Join(dir, "best.table")
+	if err := table.Save(tableFile); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := LoadTableAgent(tableFile, 1)
+	if err != nil {
+		t.Fatalf("LoadTableAgent() error = %v", err)
+	}
+	if move := loaded.GetMove(game.NewBoard(), 1); move != tableMove {
+		t.Errorf("loaded table agent move = %v, want %v", move, tableMove)
+	}
+
+	network := NewNetworkGenome(rng)
+	networkMove := network.Agent(1).GetMove(game.NewBoard(), 1)
+	networkFile := filepath.Join(dir, "best.network")
+	if err := network.Save(networkFile); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err = LoadNetworkAgent(networkFile, 1)
+	if err != nil {
+		t.Fatalf("LoadNetworkAgent() error = %v", err)
+	}
+	if move := loaded.GetMove(game.NewBoard(), 1); move != networkMove {
+		t.Errorf("loaded network agent move = %v, want %v", move, networkMove)
+	}
+}
+
+func TestTableGenomeServesBothSeats(t *testing.T) {
+	g := NewTableGenome(rand.New(rand.NewSource(1)))
+	// X to move with two in the top row: take the third cell
+	g.Preferences["110220000"] = []float64{0, 0, 1, 0, 0, 0, 0, 0, 0}
+
+	// The same position for O
+	if move := g.Agent(2).GetMove(game.FromStateString("220110000"), 2); move != 2 {
+		t.Errorf("table agent as O: GetMove() = %d, want the winning move 2", move)
+	}
+
+	path := filepath.Join(t.TempDir(), "best.table")
+	if err := g.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadTableAgent(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if move := loaded.GetMove(game.FromStateString("220110000"), 2); move != 2 {
+		t.Errorf("loaded table agent as O: GetMove() = %d, want 2", move)
+	}
+}
@@ -1,7 +1,9 @@
 package game
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 )
 
 // Board represents the game grid
@@ -145,6 +147,23 @@ func (b *Board) IsGameOver() (bool, int) {
 	return true, 0
 }
 
+// WinningLine returns the three positions of the completed line, or nil if
+// nobody has won
+func (b *Board) WinningLine() []int {
+	lines := [][3]int{
+		{0, 1, 2}, {3, 4, 5}, {6, 7, 8},
+		{0, 3, 6}, {1, 4, 7}, {2, 5, 8},
+		{0, 4, 8}, {2, 4, 6},
+	}
+	for _, line := range lines {
+		first := b.GetCell(line[0])
+		if first != 0 && first == b.GetCell(line[1]) && first == b.GetCell(line[2]) {
+			return line[:]
+		}
+	}
+	return nil
+}
+
 // FromStateString creates a new board from a state string representation
 func FromStateString(state string) *Board {
 	board := NewBoard()
@@ -154,4 +173,39 @@ func FromStateString(state string) *Board {
 		board.state[row][col] = int(state[i] - '0')
 	}
 	return board
-} 
+}
+
+// ParseBoard reads a position either as a state string like "102010201" or as
+// an ASCII grid such as the output of String. X or 1 is player 1, O or 2 is
+// player 2, and _, . or 0 is an empty cell. Separator rows of dashes and the
+// characters '|', '/' and whitespace are ignored.
+func ParseBoard(s string) (*Board, error) {
+	var cells []int
+	for _, line := range strings.Split(s, "\n") {
+		if strings.Trim(line, "-+ \t\r") == "" {
+			continue
+		}
+		for _, c := range line {
+			switch c {
+			case 'X', 'x', '1':
+				cells = append(cells, 1)
+			case 'O', 'o', '2':
+				cells = append(cells, 2)
+			case '_', '.', '0':
+				cells = append(cells, 0)
+			case '|', '/', ' ', '\t', '\r':
+			default:
+				return nil, fmt.Errorf("unexpected character %q in board", c)
+			}
+		}
+	}
+	if len(cells) != 9 {
+		return nil, fmt.Errorf("board has %d cells, want 9", len(cells))
+	}
+
+	board := NewBoard()
+	for i, cell := range cells {
+		board.state[i/3][i%3] = cell
+	}
+	return board, nil
+}
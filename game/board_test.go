@@ -434,3 +434,103 @@ func TestGetAvailableMoves(t *testing.T) {
 		})
 	}
 } 
+
+func TestWinningLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		state [][]int
+		want  []int
+	}{
+		{
+			name: "no winner",
+			state: [][]int{
+				{1, 0, 2},
+				{0, 1, 0},
+				{0, 0, 0},
+			},
+			want: nil,
+		},
+		{
+			name: "column win",
+			state: [][]int{
+				{2, 1, 0},
+				{2, 1, 0},
+				{2, 0, 0},
+			},
+			want: []int{0, 3, 6},
+		},
+		{
+			name: "anti-diagonal win",
+			state: [][]int{
+				{2, 2, 1},
+				{0, 1, 0},
+				{1, 0, 0},
+			},
+			want: []int{2, 4, 6},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBoard()
+			b.SetState(tt.state)
+
+			got := b.WinningLine()
+			if len(got) != len(tt.want) {
+				t.Fatalf("WinningLine() = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("WinningLine() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseBoard(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "state string",
+			input: "102010201",
+			want:  "1|_|2\n-----\n_|1|_\n-----\n2|_|1",
+		},
+		{
+			name:  "board string output",
+			input: "1|_|2\n-----\n_|1|_\n-----\n2|_|1",
+			want:  "1|_|2\n-----\n_|1|_\n-----\n2|_|1",
+		},
+		{
+			name:  "x and o grid",
+			input: "X.O/.x./o..",
+			want:  "1|_|2\n-----\n_|1|_\n-----\n2|_|_",
+		},
+		{
+			name:    "too few cells",
+			input:   "1020",
+			wantErr: true,
+		},
+		{
+			name:    "bad character",
+			input:   "10201020z",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseBoard(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBoard(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err == nil && b.String() != tt.want {
+				t.Errorf("ParseBoard(%q) = \n%v\nwant\n%v", tt.input, b.String(), tt.want)
+			}
+		})
+	}
+}
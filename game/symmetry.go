@@ -0,0 +1,71 @@
+package game
+
+// symmetries holds the 8 rotations and reflections of the board as cell
+// permutations: the transformed state has state[perm[i]] at cell i
+var symmetries = buildSymmetries()
+
+func buildSymmetries() [8][9]int {
+	rotate := func(p [9]int) [9]int {
+		var r [9]int
+		for row := 0; row < 3; row++ {
+			for col := 0; col < 3; col++ {
+				r[row*3+col] = p[(2-col)*3+row]
+			}
+		}
+		return r
+	}
+	reflect := func(p [9]int) [9]int {
+		var r [9]int
+		for row := 0; row < 3; row++ {
+			for col := 0; col < 3; col++ {
+				r[row*3+col] = p[row*3+2-col]
+			}
+		}
+		return r
+	}
+
+	var all [8][9]int
+	p := [9]int{0, 1, 2, 3, 4, 5, 6, 7, 8}
+	for i := 0; i < 4; i++ {
+		all[i] = p
+		all[i+4] = reflect(p)
+		p = rotate(p)
+	}
+	return all
+}
+
+// Transform applies symmetry t (0-7) to a state string
+func Transform(state string, t int) string {
+	b := make([]byte, 9)
+	for i, from := range symmetries[t] {
+		b[i] = state[from]
+	}
+	return string(b)
+}
+
+// Canonical returns the smallest of the 8 symmetric variants of a state
+// string along with the symmetry that produces it
+func Canonical(state string) (string, int) {
+	best, bestT := state, 0
+	for t := 1; t < 8; t++ {
+		if s := Transform(state, t); s < best {
+			best, bestT = s, t
+		}
+	}
+	return best, bestT
+}
+
+// ToCanonical maps a position on the original board to its position after symmetry t
+func ToCanonical(pos, t int) int {
+	for i, from := range symmetries[t] {
+		if from == pos {
+			return i
+		}
+	}
+	return -1
+}
+
+// FromCanonical maps a position on the transformed board back to the original board
+func FromCanonical(pos, t int) int {
+	return symmetries[t][pos]
+}
@@ -0,0 +1,52 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		name   string
+		states []string // all should share one canonical form
+	}{
+		{
+			name:   "corner openings",
+			states: []string{"100000000", "001000000", "000000100", "000000001"},
+		},
+		{
+			name:   "edge openings",
+			states: []string{"010000000", "000100000", "000001000", "000000010"},
+		},
+		{
+			name:   "mixed position",
+			states: []string{"120000000", "100200000", "021000000", "000000120"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, _ := Canonical(tt.states[0])
+			for _, state := range tt.states[1:] {
+				if got, _ := Canonical(state); got != want {
+					t.Errorf("Canonical(%v) = %v, want %v", state, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCanonicalPositions(t *testing.T) {
+	state := "102000000"
+	for tr := 0; tr < 8; tr++ {
+		transformed := Transform(state, tr)
+		for pos := 0; pos < 9; pos++ {
+			canonicalPos := ToCanonical(pos, tr)
+			if transformed[canonicalPos] != state[pos] {
+				t.Errorf("symmetry %d: cell %d moved to %d but values differ", tr, pos, canonicalPos)
+			}
+			if back := FromCanonical(canonicalPos, tr); back != pos {
+				t.Errorf("symmetry %d: FromCanonical(ToCanonical(%d)) = %d", tr, pos, back)
+			}
+		}
+	}
+}
@@ -0,0 +1,320 @@
+// Package league trains an agent against a growing pool of opponents: frozen
+// snapshots of its own past selves alongside fixed agents such as minimax and
+// random. Playing many different opponents keeps the learner from narrowing
+// to whatever beats its current self. The pool is saved to a directory so a
+// league can be stopped and resumed.
+package league
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jpotts18/tictactoe/agent"
+	"github.com/jpotts18/tictactoe/reward"
+	"github.com/jpotts18/tictactoe/stats"
+	"github.com/jpotts18/tictactoe/training"
+)
+
+// Sampling is how opponents are drawn from the pool
+type Sampling int
+
+const (
+	// Uniform draws every member, snapshot or fixed, with equal probability
+	Uniform Sampling = iota
+
+	// Prioritized draws members in proportion to (1 - score)^2, where score is
+	// the learner's match score against them, so the opponents it struggles
+	// with come up most often
+	Prioritized
+
+	// FictitiousPlay draws past snapshots uniformly, so the learner plays the
+	// average of its own past policies. Fixed members are only drawn before
+	// the first snapshot.
+	FictitiousPlay
+)
+
+func (s Sampling) String() string {
+	switch s {
+	case Prioritized:
+		return "prioritized"
+	case FictitiousPlay:
+		return "fictitious"
+	default:
+		return "uniform"
+	}
+}
+
+// ParseSampling reads a sampling scheme name as printed by String
+func ParseSampling(name string) (Sampling, error) {
+	for _, s := range []Sampling{Uniform, Prioritized, FictitiousPlay} {
+		if s.String() == name {
+			return s, nil
+		}
+	}
+	return Uniform, fmt.Errorf("unknown sampling %q, expected uniform, prioritized or fictitious", name)
+}
+
+// Frozen plays a fixed copy of an agent: exploration is off and Learn does
+// nothing
+type Frozen struct {
+	agent.Agent
+}
+
+// Freeze turns off a's exploration and wraps it so it stops learning
+func Freeze(a agent.Agent) *Frozen {
+	if evaluating, ok := a.(agent.EvaluatingAgent); ok {
+		evaluating.SetEvaluating(true)
+	}
+	return &Frozen{a}
+}
+
+func (f *Frozen) Learn(state string, action int, reward float64, newState string) {}
+
+// SetPlayer seats the wrapped agent, if it can change seats
+func (f *Frozen) SetPlayer(player int) {
+	if seated, ok := f.Agent.(agent.SeatedAgent); ok {
+		seated.SetPlayer(player)
+	}
+}
+
+// Member is an opponent in the pool, with the learner's results against it
+type Member struct {
+	Name   string `json:"name"`
+	File   string `json:"file,omitempty"` // snapshot model, empty for fixed members
+	Wins   int    `json:"wins"`
+	Draws  int    `json:"draws"`
+	Losses int    `json:"losses"`
+
+	Agent agent.Agent `json:"-"`
+}
+
+// Snapshot reports whether the member is a frozen copy of the learner
+func (m *Member) Snapshot() bool {
+	return m.File != ""
+}
+
+// Score returns the learner's match score against the member
+func (m *Member) Score() float64 {
+	return stats.Score(m.Wins, m.Draws, m.Losses)
+}
+
+// Pool is the state of a league that is saved between runs
+type Pool struct {
+	Games   int       `json:"games"` // training games played so far
+	Members []*Member `json:"members"`
+}
+
+// weight is the relative probability of drawing m
+func (s Sampling) weight(m *Member, snapshots int) float64 {
+	switch s {
+	case Prioritized:
+		return math.Pow(1-m.Score(), 2)
+	case FictitiousPlay:
+		if snapshots > 0 && !m.Snapshot() {
+			return 0
+		}
+	}
+	return 1
+}
+
+// Sample draws an opponent by s from the members that have an agent, or
+// returns nil if there are none
+func (p *Pool) Sample(s Sampling, rng *rand.Rand) *Member {
+	var available []*Member
+	snapshots := 0
+	for _, m := range p.Members {
+		if m.Agent != nil {
+			available = append(available, m)
+			if m.Snapshot() {
+				snapshots++
+			}
+		}
+	}
+	if len(available) == 0 {
+		return nil
+	}
+
+	weights := make([]float64, len(available))
+	total := 0.0
+	for i, m := range available {
+		weights[i] = s.weight(m, snapshots)
+		total += weights[i]
+	}
+	// If the learner beats everyone, fall back to a uniform draw
+	if total == 0 {
+		return available[rng.Intn(len(available))]
+	}
+	r := rng.Float64() * total
+	for i, w := range weights {
+		if r < w {
+			return available[i]
+		}
+		r -= w
+	}
+	return available[len(available)-1]
+}
+
+// Config sets how a league is played
+type Config struct {
+	Sampling      Sampling
+	SelfPlay      float64 // share of games the learner plays against itself
+	SnapshotEvery int     // training games between snapshots
+	Rewards       reward.Function
+	Seed          int64 // seeds opponent sampling; 0 takes one from the clock
+}
+
+// DefaultConfig snapshots every 5000 games and plays a fifth of the games
+// by self-play
+func DefaultConfig() Config {
+	return Config{
+		Sampling:      Uniform,
+		SelfPlay:      0.2,
+		SnapshotEvery: 5000,
+		Rewards:       reward.Sparse(),
+	}
+}
+
+// League trains one learner against its pool
+type League struct {
+	Config
+	dir      string
+	learner  agent.LearningAgent
+	newAgent func() agent.LearningAgent
+	pool     Pool
+	rng      *rand.Rand
+}
+
+const poolFile = "pool.json"
+
+// Open starts a league in dir, or resumes the one saved there: the learner
+// and every snapshot are loaded back, and results carry on from where they
+// stopped. newAgent builds the empty agents snapshots are loaded into.
+func Open(dir string, learner agent.LearningAgent, newAgent func() agent.LearningAgent, config Config) (*League, error) {
+	l := &League{
+		Config:   config,
+		dir:      dir,
+		learner:  learner,
+		newAgent: newAgent,
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	l.rng = rand.New(rand.NewSource(seed))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, poolFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &l.pool); err != nil {
+		return nil, fmt.Errorf("reading %s: %v", poolFile, err)
+	}
+	if err := learner.Load(filepath.Join(dir, "learner")); err != nil {
+		return nil, fmt.Errorf("loading learner: %v", err)
+	}
+	for _, m := range l.pool.Members {
+		if !m.Snapshot() {
+			continue
+		}
+		snapshot := newAgent()
+		if err := snapshot.Load(filepath.Join(dir, m.File)); err != nil {
+			return nil, fmt.Errorf("loading snapshot %s: %v", m.Name, err)
+		}
+		m.Agent = Freeze(snapshot)
+	}
+	return l, nil
+}
+
+// AddFixed adds a fixed opponent, or attaches a to the saved member of that
+// name when resuming
+func (l *League) AddFixed(name string, a agent.Agent) {
+	for _, m := range l.pool.Members {
+		if m.Name == name && !m.Snapshot() {
+			m.Agent = a
+			return
+		}
+	}
+	l.pool.Members = append(l.pool.Members, &Member{Name: name, Agent: a})
+}
+
+// Games returns the number of training games played so far
+func (l *League) Games() int {
+	return l.pool.Games
+}
+
+// Members returns the pool, fixed members and snapshots in the order added
+func (l *League) Members() []*Member {
+	return l.pool.Members
+}
+
+// Snapshot freezes a copy of the learner and adds it to the pool
+func (l *League) Snapshot() error {
+	name := fmt.Sprintf("snapshot-%07d", l.pool.Games)
+	path := filepath.Join(l.dir, name)
+	if err := l.learner.Save(path); err != nil {
+		return err
+	}
+	snapshot := l.newAgent()
+	if err := snapshot.Load(path); err != nil {
+		return err
+	}
+	l.pool.Members = append(l.pool.Members, &Member{Name: name, File: name, Agent: Freeze(snapshot)})
+	return nil
+}
+
+// Train plays games training games, each against itself or an opponent
+// drawn from the pool, taking a snapshot every SnapshotEvery games. The
+// league is saved at the end.
+func (l *League) Train(games int) error {
+	for i := 0; i < games; i++ {
+		opponent := l.pool.Sample(l.Sampling, l.rng)
+		if opponent == nil || l.rng.Float64() < l.SelfPlay {
+			x, o := training.Seats(l.learner)
+			training.Match(x, o, l.pool.Games, l.Rewards)
+		} else {
+			// Counting the member's games alternates the learner between X
+			// and O against each member
+			switch training.Match(l.learner, opponent.Agent, opponent.Wins+opponent.Draws+opponent.Losses, l.Rewards) {
+			case 1:
+				opponent.Wins++
+			case 0:
+				opponent.Draws++
+			default:
+				opponent.Losses++
+			}
+		}
+
+		l.pool.Games++
+		if l.SnapshotEvery > 0 && l.pool.Games%l.SnapshotEvery == 0 {
+			if err := l.Snapshot(); err != nil {
+				return err
+			}
+		}
+	}
+	return l.Save()
+}
+
+// Save writes the learner and the pool to the league directory
+func (l *League) Save() error {
+	if err := l.learner.Save(filepath.Join(l.dir, "learner")); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(l.pool, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(l.dir, poolFile), data, 0644)
+}
@@ -0,0 +1,154 @@
+package league
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jpotts18/tictactoe/agent"
+	"github.com/jpotts18/tictactoe/game"
+)
+
+// draws samples n opponents and counts them by name
+func draws(p *Pool, s Sampling, n int) map[string]int {
+	rng := rand.New(rand.NewSource(1))
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		counts[p.Sample(s, rng).Name]++
+	}
+	return counts
+}
+
+func testPool() *Pool {
+	return &Pool{Members: []*Member{
+		{Name: "random", Wins: 90, Draws: 10, Agent: agent.NewRandomAgent(2)},
+		{Name: "minimax", Draws: 50, Losses: 50, Agent: agent.NewMinimaxAgent(2)},
+		{Name: "snapshot-1", File: "snapshot-1", Wins: 50, Losses: 50, Agent: agent.NewRandomAgent(2)},
+		{Name: "snapshot-2", File: "snapshot-2", Wins: 50, Losses: 50, Agent: agent.NewRandomAgent(2)},
+		{Name: "unloaded", File: "unloaded"},
+	}}
+}
+
+func TestSample(t *testing.T) {
+	n := 10000
+	uniform := draws(testPool(), Uniform, n)
+	for _, name := range []string{"random", "minimax", "snapshot-1", "snapshot-2"} {
+		if uniform[name] < n/5 || uniform[name] > n*3/10 {
+			t.Errorf("uniform drew %s %d times in %d, want about a quarter", name, uniform[name], n)
+		}
+	}
+	if uniform["unloaded"] != 0 {
+		t.Errorf("uniform drew a member without an agent %d times", uniform["unloaded"])
+	}
+
+	// Scores 0.95, 0.25, 0.5 and 0.5 give weights 0.0025, 0.5625, 0.25, 0.25
+	prioritized := draws(testPool(), Prioritized, n)
+	if prioritized["minimax"] < n/2 || prioritized["random"] > n/100 {
+		t.Errorf("prioritized draws = %v, want mostly minimax and almost never random", prioritized)
+	}
+
+	fictitious := draws(testPool(), FictitiousPlay, n)
+	if fictitious["random"]+fictitious["minimax"] != 0 {
+		t.Errorf("fictitious draws = %v, want snapshots only", fictitious)
+	}
+	fixedOnly := &Pool{Members: testPool().Members[:2]}
+	if got := draws(fixedOnly, FictitiousPlay, 100); got["random"] == 0 || got["minimax"] == 0 {
+		t.Errorf("fictitious draws without snapshots = %v, want the fixed members", got)
+	}
+
+	if m := (&Pool{}).Sample(Uniform, rand.New(rand.NewSource(1))); m != nil {
+		t.Errorf("Sample() of an empty pool = %v, want nil", m)
+	}
+}
+
+func TestParseSampling(t *testing.T) {
+	for _, s := range []Sampling{Uniform, Prioritized, FictitiousPlay} {
+		if got, err := ParseSampling(s.String()); err != nil || got != s {
+			t.Errorf("ParseSampling(%q) = %v, %v, want %v", s, got, err, s)
+		}
+	}
+	if _, err := ParseSampling("elo"); err == nil {
+		t.Errorf("ParseSampling(\"elo\") succeeded, want an error")
+	}
+}
+
+func TestFrozen(t *testing.T) {
+	q := agent.NewQAgent(1)
+	board := game.NewBoard()
+	state := q.GetStateKey(board)
+	q.Learn(state, 4, 1, "")
+	frozen := Freeze(q)
+	for i := 0; i < 20; i++ {
+		frozen.Learn(state, 0, 1, "")
+	}
+	if got := q.GetQValues(state); got[0] != 0 {
+		t.Errorf("Q(%s, 0) = %v after frozen updates, want 0", state, got[0])
+	}
+	for i := 0; i < 20; i++ {
+		if move := frozen.GetMove(board, 1); move != 4 {
+			t.Fatalf("frozen GetMove() = %d, want the greedy move 4", move)
+		}
+	}
+	frozen.SetPlayer(2)
+	if q.Player != 2 {
+		t.Errorf("SetPlayer(2) left the agent as player %d", q.Player)
+	}
+}
+
+func TestLeagueResumes(t *testing.T) {
+	dir := t.TempDir()
+	newAgent := func() agent.LearningAgent { return agent.NewQAgent(1) }
+	config := DefaultConfig()
+	config.SnapshotEvery = 100
+
+	learner := agent.NewQAgent(1)
+	l, err := Open(dir, learner, newAgent, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.AddFixed("random", agent.NewRandomAgent(2))
+	if err := l.Train(300); err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Members()) != 4 {
+		t.Fatalf("pool has %d members after 300 games, want random and 3 snapshots", len(l.Members()))
+	}
+	for _, file := range []string{"pool.json", "learner.qlearning", "snapshot-0000300.qlearning"} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("league directory is missing %s: %v", file, err)
+		}
+	}
+
+	resumedLearner := agent.NewQAgent(1)
+	resumed, err := Open(dir, resumedLearner, newAgent, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed.AddFixed("random", agent.NewRandomAgent(2))
+	if resumed.Games() != 300 {
+		t.Errorf("resumed league has played %d games, want 300", resumed.Games())
+	}
+	if resumedLearner.TableSize() != learner.TableSize() {
+		t.Errorf("resumed learner has %d states, want %d", resumedLearner.TableSize(), learner.TableSize())
+	}
+	if len(resumed.Members()) != 4 {
+		t.Fatalf("resumed pool has %d members, want 4", len(resumed.Members()))
+	}
+	for i, m := range resumed.Members() {
+		before := l.Members()[i]
+		if m.Name != before.Name || m.Wins+m.Draws+m.Losses != before.Wins+before.Draws+before.Losses {
+			t.Errorf("resumed member %d = %+v, want %+v", i, m, before)
+		}
+		if m.Agent == nil {
+			t.Errorf("resumed member %s has no agent", m.Name)
+		}
+	}
+
+	if err := resumed.Train(100); err != nil {
+		t.Fatal(err)
+	}
+	if last := resumed.Members()[len(resumed.Members())-1]; last.Name != "snapshot-0000400" {
+		t.Errorf("latest snapshot after resuming = %s, want snapshot-0000400", last.Name)
+	}
+}
@@ -4,117 +4,30 @@ import (
 	"flag"
 	"fmt"
 	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/jpotts18/tictactoe/agent"
+	"github.com/jpotts18/tictactoe/alphazero"
+	"github.com/jpotts18/tictactoe/config"
+	"github.com/jpotts18/tictactoe/evolution"
 	"github.com/jpotts18/tictactoe/game"
+	"github.com/jpotts18/tictactoe/league"
+	"github.com/jpotts18/tictactoe/metrics"
+	"github.com/jpotts18/tictactoe/plot"
+	"github.com/jpotts18/tictactoe/reward"
+	"github.com/jpotts18/tictactoe/solver"
+	"github.com/jpotts18/tictactoe/stats"
+	"github.com/jpotts18/tictactoe/sweep"
+	"github.com/jpotts18/tictactoe/training"
+	"github.com/jpotts18/tictactoe/tui"
 )
 
-// RewardScheme holds the reward values for different game outcomes
-type RewardScheme struct {
-	win   float64
-	draw  float64
-	loss  float64
-	step  float64
-}
-
-func evaluateAgents(agent1 agent.Agent, agent2 agent.Agent, numGames int, rewards RewardScheme) (wins, draws, losses int) {
-	for i := 0; i < numGames; i++ {
-		board := game.NewBoard()
-		
-		// Randomly decide who goes first
-		agent1GoesFirst := rand.Float64() < 0.5
-		var firstAgent, secondAgent agent.Agent
-		var firstPlayer, secondPlayer int
-		
-		if agent1GoesFirst {
-			firstAgent, secondAgent = agent1, agent2
-			firstPlayer, secondPlayer = 1, 2
-		} else {
-			firstAgent, secondAgent = agent2, agent1
-			firstPlayer, secondPlayer = 2, 1
-		}
-
-		for {
-			// First agent's turn
-			oldState := firstAgent.GetStateKey(board)
-			move := firstAgent.ChooseAction(board)
-			board.MakeMove(move, firstPlayer)
-			
-			gameOver, winner := board.IsGameOver()
-			if gameOver {
-				var reward float64
-				if agent1GoesFirst {
-					if winner == 1 {
-						wins++
-						reward = rewards.win
-					} else if winner == 0 {
-						draws++
-						reward = rewards.draw
-					} else {
-						losses++
-						reward = rewards.loss
-					}
-				} else {
-					if winner == 2 {
-						losses++
-						reward = rewards.loss
-					} else if winner == 0 {
-						draws++
-						reward = rewards.draw
-					} else {
-						wins++
-						reward = rewards.win
-					}
-				}
-				firstAgent.Learn(oldState, move, reward, firstAgent.GetStateKey(board))
-				break
-			} else {
-				firstAgent.Learn(oldState, move, rewards.step, firstAgent.GetStateKey(board))
-			}
-			
-			// Second agent's turn
-			oldState = secondAgent.GetStateKey(board)
-			move = secondAgent.ChooseAction(board)
-			board.MakeMove(move, secondPlayer)
-			
-			gameOver, winner = board.IsGameOver()
-			if gameOver {
-				var reward float64
-				if agent1GoesFirst {
-					if winner == 1 {
-						wins++
-						reward = -rewards.win
-					} else if winner == 0 {
-						draws++
-						reward = rewards.draw
-					} else {
-						losses++
-						reward = -rewards.loss
-					}
-				} else {
-					if winner == 2 {
-						losses++
-						reward = -rewards.win
-					} else if winner == 0 {
-						draws++
-						reward = rewards.draw
-					} else {
-						wins++
-						reward = -rewards.loss
-					}
-				}
-				secondAgent.Learn(oldState, move, reward, secondAgent.GetStateKey(board))
-				break
-			} else {
-				secondAgent.Learn(oldState, move, rewards.step, secondAgent.GetStateKey(board))
-			}
-		}
-	}
-	return
-}
-
 // Add this helper function for consistent output format
 func printResults(name string, wins, draws, losses, numGames int) {
 	fmt.Printf("Results after %d games:\n", numGames)
@@ -126,7 +39,7 @@ func printResults(name string, wins, draws, losses, numGames int) {
 
 // Add this helper function to evaluate Q-Learning progress
 func evaluateProgress(qagent agent.Agent, opponent agent.Agent, numGames int) (winRate, drawRate float64) {
-	wins, draws, _ := evaluateAgents(qagent, opponent, numGames, RewardScheme{})
+	wins, draws, _ := training.Play(qagent, opponent, numGames, nil)
 	return float64(wins)/float64(numGames)*100, float64(draws)/float64(numGames)*100
 }
 
@@ -137,106 +50,455 @@ func main() {
 	trainCmd := flag.Bool("train", false, "Train all models")
 	evalCmd := flag.Bool("evaluate", false, "Evaluate trained models")
 	playCmd := flag.Bool("play", false, "Play against a trained model")
+	selfPlayCmd := flag.Bool("selfplay", false, "Train the AlphaZero-style model by self-play")
+	evolveCmd := flag.Bool("evolve", false, "Evolve a policy with a genetic algorithm")
+	matchboxesCmd := flag.Bool("matchboxes", false, "Print the trained MENACE matchbox contents")
+	analyzeCmd := flag.String("analyze", "", "Analyze a position given as a state string or ASCII grid")
+	tablebaseCmd := flag.Bool("tablebase", false, "Solve every position and save the tablebase")
+	plotCmd := flag.String("plot", "", "Render charts from metrics files or run directories (comma-separated)")
+	compareCmd := flag.Bool("compare", false, "Compare reward schemes and chart the results")
+	sprtCmd := flag.String("sprt", "", "Run a sequential test between two agents, e.g. Q-Learning,SARSA")
+	defaultSPRT := stats.DefaultSPRT()
+	elo0 := flag.Float64("elo0", defaultSPRT.Elo0, "SPRT null hypothesis Elo difference")
+	elo1 := flag.Float64("elo1", defaultSPRT.Elo1, "SPRT alternative hypothesis Elo difference")
+	alpha := flag.Float64("alpha", defaultSPRT.Alpha, "SPRT false-positive rate")
+	beta := flag.Float64("beta", defaultSPRT.Beta, "SPRT false-negative rate")
+	maxGames := flag.Int("maxgames", 100000, "Maximum games for the SPRT")
+	sweepCmd := flag.String("sweep", "", "Search hyperparameters of qlearning, sarsa or montecarlo")
+	search := flag.String("search", "grid", "Sweep search: grid, random or halving")
+	trials := flag.Int("trials", 16, "Candidates for random and halving search")
+	sweepGames := flag.Int("games", 20000, "Training games per sweep candidate (first round for halving) or for -league")
+	workers := flag.Int("workers", runtime.NumCPU(), "Candidates trained in parallel")
+	exploreSpec := flag.String("explore", "", "Exploration for tabular agents, e.g. boltzmann or ucb:c=1")
+	configFile := flag.String("config", "", "Run the experiment described by a JSON file with -train")
+	curriculumSpec := flag.String("curriculum", "", "Train against a curriculum of opponents: a JSON file or \"default\"")
+	leagueCmd := flag.String("league", "", "Train qlearning, sarsa or montecarlo against a league of its past snapshots")
+	sampling := flag.String("sampling", "uniform", "League opponent sampling: uniform, prioritized or fictitious")
+	seed := flag.Int64("seed", 0, "Random seed for -sweep and -league; 0 takes one from the clock")
+	var agentSpecs specList
+	flag.Var(&agentSpecs, "agent", "Agent for -play and -evaluate, e.g. qlearning:models/qlearning or minimax:depth=3 (repeatable)")
 	flag.Parse()
 
-	if !*trainCmd && !*evalCmd && !*playCmd {
+	if _, err := agent.ParseExplorer(*exploreSpec); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if *trials < 1 {
+		fmt.Printf("-trials must be at least 1, got %d\n", *trials)
+		return
+	}
+	for _, spec := range agentSpecs {
+		if _, err := agent.New(spec, 1); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	curriculum, err := loadCurriculum(*curriculumSpec)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if !*trainCmd && !*evalCmd && !*playCmd && !*selfPlayCmd && !*evolveCmd && !*matchboxesCmd && *analyzeCmd == "" &&
+		!*tablebaseCmd && *plotCmd == "" && !*compareCmd && *sprtCmd == "" && *sweepCmd == "" && *leagueCmd == "" {
 		fmt.Println("Please specify one of the following commands:")
 		fmt.Println("  -train     Train all models")
-		fmt.Println("  -evaluate  Evaluate trained models")
-		fmt.Println("  -play      Play against a trained model")
+		fmt.Println("             (-curriculum <file>|default to train against opponents of rising strength,")
+		fmt.Println("             -config <file> to run an experiment file)")
+		fmt.Println("  -evaluate  Evaluate trained models (or each -agent <spec>)")
+		fmt.Println("  -play      Play against a trained model (or -agent <spec>)")
+		fmt.Println("  -selfplay  Train the AlphaZero-style model by self-play")
+		fmt.Println("  -evolve    Evolve a policy with a genetic algorithm")
+		fmt.Println("  -matchboxes Print the trained MENACE matchbox contents")
+		fmt.Println("  -analyze <position>  Show perfect-play values and model Q-values")
+		fmt.Println("  -tablebase Solve every position and save the tablebase")
+		fmt.Println("  -plot <files or dirs>  Render learning-curve charts as SVG and PNG")
+		fmt.Println("  -compare   Compare reward schemes and chart the results")
+		fmt.Println("  -sprt <a>,<b>  Play until the test decides whether a is stronger than b")
+		fmt.Println("             (a name uses its latest model in models/, or give a spec like minimax:depth=3)")
+		fmt.Println("             (tune with -elo0, -elo1, -alpha, -beta and -maxgames)")
+		fmt.Println("  -sweep <agent>  Search hyperparameters of qlearning, sarsa or montecarlo")
+		fmt.Println("             (-search grid|random|halving, -trials, -games, -workers)")
+		fmt.Println("  -league <agent>  Train against a pool of past snapshots, minimax and random")
+		fmt.Println("             (-sampling uniform|prioritized|fictitious, -games; resumes from models/league-<agent>)")
+		fmt.Println("  -explore <spec>  Exploration for -train, -compare, -sweep and -league:")
+		fmt.Println("             epsilon, boltzmann, ucb or optimistic, e.g. epsilon:schedule=linear")
+		fmt.Println("  -agent <name>[:model][,key=value...]  Agent spec; registered agents are")
+		fmt.Printf("             %s\n", strings.Join(agent.Names(), ", "))
 		return
 	}
 
-	if *trainCmd {
-		trainModels()
+	if *tablebaseCmd {
+		saveTablebase()
+	}
+	if *trainCmd && *configFile != "" {
+		if *exploreSpec != "" || curriculum != nil {
+			fmt.Println("-explore and -curriculum cannot be combined with -config; set them in the experiment file")
+			return
+		}
+		runExperiment(*configFile)
+	} else if *trainCmd {
+		trainModels(*exploreSpec, curriculum)
+	}
+	if *selfPlayCmd {
+		trainAlphaZero()
+	}
+	if *evolveCmd {
+		evolvePolicy()
+	}
+	if *matchboxesCmd {
+		menaceAgent := agent.NewMenaceAgent(1, agent.DefaultBeadRules())
+		if err := menaceAgent.Load("models/menace"); err != nil {
+			fmt.Println("No trained MENACE model found")
+		} else {
+			menaceAgent.Report(os.Stdout)
+		}
+	}
+	if *compareCmd {
+		compareRewardSchemes(*exploreSpec)
+	}
+	if *plotCmd != "" {
+		plotRuns(strings.Split(*plotCmd, ","))
 	}
 	if *evalCmd {
-		evaluateModels()
+		evaluateModels(agentSpecs)
+	}
+	if *sweepCmd != "" {
+		runSweep(*sweepCmd, *search, *trials, *sweepGames, *workers, *exploreSpec, *seed)
+	}
+	if *leagueCmd != "" {
+		runLeague(*leagueCmd, *sampling, *sweepGames, *exploreSpec, *seed)
+	}
+	if *sprtCmd != "" {
+		runSPRT(*sprtCmd, stats.SPRT{Elo0: *elo0, Elo1: *elo1, Alpha: *alpha, Beta: *beta}, *maxGames)
+	}
+	if *analyzeCmd != "" {
+		analyzePosition(*analyzeCmd)
 	}
 	if *playCmd {
-		playGame()
+		playGame(agentSpecs)
 	}
 }
 
-func trainModels() {
+// specList collects the values of a repeatable flag
+type specList []string
+
+func (s *specList) String() string {
+	return strings.Join(*s, " ")
+}
+
+func (s *specList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// saveTablebase solves every reachable position and writes the tablebase to
+// solver.DefaultFile, where later runs load it instead of solving again
+func saveTablebase() {
+	tb := solver.Build()
+	os.MkdirAll(filepath.Dir(solver.DefaultFile), 0755)
+	if err := tb.Save(solver.DefaultFile); err != nil {
+		fmt.Printf("Error saving tablebase: %v\n", err)
+		return
+	}
+	fmt.Printf("Solved %d positions, saved to %s (%d bytes)\n", tb.Len(), solver.DefaultFile, solver.TablebaseSize)
+}
+
+// loadCurriculum reads the curriculum named by -curriculum: a JSON file, or
+// "default" for training.DefaultCurriculum. An empty spec means self-play.
+func loadCurriculum(spec string) (*training.Curriculum, error) {
+	switch spec {
+	case "":
+		return nil, nil
+	case "default":
+		c := training.DefaultCurriculum()
+		return &c, nil
+	}
+	c, err := training.LoadCurriculum(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// trainModels trains every learning agent by self-play, or through
+// curriculum when it is not nil
+func trainModels(exploreSpec string, curriculum *training.Curriculum) {
 	fmt.Println("=== Training Models ===")
 	
-	// Create learning agents
-	qagent := agent.NewQAgent(1)
-	sarsaAgent := agent.NewSarsaAgent(1)
-	mcAgent := agent.NewMonteCarloAgent(1)
-	
-	// Training configurations
-	iterations := 100000
-	evalFrequency := 5000
-	benchmarkRandom := agent.NewRandomAgent(2)
+	// Train the default experiment's agents with its schedule and benchmarks
+	exp := config.DefaultExperiment()
+	benchmarks := newBenchmarks(exp.Evaluation.Opponents)
+
+	// Learning curves are written to runs/<start time>/<agent>.csv and .jsonl
+	runDir := filepath.Join("runs", time.Now().Format("20060102-150405"))
+	if err := os.MkdirAll(runDir, 0755); err != nil {
+		fmt.Println("Failed to create run directory:", err)
+		return
+	}
 
 	// Train each agent
-	trainAgent("Q-Learning", qagent, iterations, evalFrequency, benchmarkRandom)
-	trainAgent("SARSA", sarsaAgent, iterations, evalFrequency, benchmarkRandom)
-	trainAgent("Monte Carlo", mcAgent, iterations, evalFrequency, benchmarkRandom)
+	learners := make([]agent.LearningAgent, len(exp.Agents))
+	for i, a := range exp.Agents {
+		learner, err := a.New(1)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		setExplorers(exploreSpec, learner)
+		learners[i] = learner
+		trainAgent(agentTitle(a.Type), learner, exp.Training.Games, exp.Evaluation.Every, benchmarks,
+			curriculum, selfPlayRewards, filepath.Join(runDir, a.Type), time.Now().UnixNano())
+	}
+	fmt.Printf("Learning curves written to %s\n", runDir)
+	plotRuns([]string{runDir})
+
+	// Save each model as models/<agent type>
+	if err := os.MkdirAll("models", 0755); err != nil {
+		fmt.Println("Failed to create models directory:", err)
+		return
+	}
+	for i, a := range exp.Agents {
+		filename := filepath.Join("models", a.Type)
+		if err := learners[i].Save(filename); err != nil {
+			fmt.Printf("Failed to save %s: %v\n", filename, err)
+		}
+	}
+}
+
+// newBenchmarks builds player 2 for each agent spec
+func newBenchmarks(specs []string) []benchmark {
+	var benchmarks []benchmark
+	for _, spec := range specs {
+		opponent, err := agent.New(spec, 2)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		benchmarks = append(benchmarks, benchmark{spec, opponent})
+	}
+	return benchmarks
+}
+
+// runExperiment trains the agents of an experiment file. The resolved
+// experiment, with its seed and defaults filled in, is written to
+// experiment.json in the output directory beside the models, learning curves
+// and charts, so the run can be repeated. Every agent, opponent and schedule
+// draws its seed from the experiment seed.
+func runExperiment(filename string) {
+	exp, err := config.Load(filename)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	exp.Resolve(time.Now())
+	rng := rand.New(rand.NewSource(exp.Seed))
+	fmt.Printf("=== Experiment %s (seed %d) ===\n", exp.Name, exp.Seed)
+
+	if err := os.MkdirAll(exp.Output, 0755); err != nil {
+		fmt.Println("Failed to create output directory:", err)
+		return
+	}
+	if err := exp.Save(filepath.Join(exp.Output, "experiment.json")); err != nil {
+		fmt.Println("Failed to save experiment:", err)
+		return
+	}
+
+	benchmarks := newBenchmarks(exp.Evaluation.Opponents)
+	for _, b := range benchmarks {
+		if seeder, ok := b.agent.(agent.Seeder); ok {
+			seeder.Seed(rng.Int63())
+		}
+	}
+	for _, a := range exp.Agents {
+		learner, err := a.New(rng.Int63())
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		path := filepath.Join(exp.Output, a.Name)
+		trainAgent(a.Name, learner, exp.Training.Games, exp.Evaluation.Every, benchmarks,
+			exp.Training.Schedule(), exp.Rewards.Function(), path, rng.Int63())
+		if err := learner.Save(path); err != nil {
+			fmt.Printf("Failed to save %s: %v\n", path, err)
+		}
+	}
+	fmt.Printf("Models, learning curves and the resolved experiment written to %s\n", exp.Output)
+	plotRuns([]string{exp.Output})
+}
+
+func trainAlphaZero() {
+	fmt.Println("=== AlphaZero Self-Play ===")
+
+	newRoot := func() alphazero.State {
+		return alphazero.NewTicTacToeState(game.NewBoard(), 1)
+	}
+	pipeline := alphazero.NewPipeline(alphazero.DefaultConfig(), alphazero.NewTabularModel(9), newRoot, os.Stdout)
+	promotions := pipeline.Run()
+	fmt.Printf("Promoted %d models\n", promotions)
+
+	os.MkdirAll("models", 0755)
+	if err := pipeline.Best.Save("models/alphazero.alphazero"); err != nil {
+		fmt.Println("Failed to save AlphaZero model:", err)
+	}
+}
+
+func evolvePolicy() {
+	fmt.Println("=== Evolving Policy ===")
 
-	// Save only learning agents
-	if learningAgent, ok := qagent(agent.LearningAgent); ok {
-		learningAgent.Save("models/qagent")
+	// Score each individual as (wins + draws/2) per game against both benchmarks.
+	// Opponents are created per call so concurrent evaluations don't share state.
+	fitness := func(policy agent.Agent) float64 {
+		numGames := 50
+		score := 0.0
+		for _, opponent := range []agent.Agent{agent.NewRandomAgent(2), agent.NewMinimaxAgent(2)} {
+			wins, draws, _ := training.Play(policy, opponent, numGames, nil)
+			score += (float64(wins) + 0.5*float64(draws)) / float64(numGames)
+		}
+		return score / 2
 	}
-	if learningAgent, ok := sarsaAgent.(agent.LearningAgent); ok {
-		learningAgent.Save("models/sarsa")
+
+	newGenome := func(rng *rand.Rand) evolution.Genome {
+		return evolution.NewTableGenome(rng)
 	}
-	if learningAgent, ok := mcAgent.(agent.LearningAgent); ok {
-		learningAgent.Save("models/montecarlo")
+	trainer := evolution.NewTrainer(evolution.DefaultConfig(), newGenome, fitness, os.Stdout)
+	best, _ := trainer.Run()
+
+	os.MkdirAll("models", 0755)
+	if err := best.Save("models/evolved.table"); err != nil {
+		fmt.Println("Failed to save evolved policy:", err)
 	}
 }
 
-func trainAgent(name string, trainAgent agent.Agent, iterations, evalFrequency int, benchmark agent.Agent) {
+// selfPlayRewards is the reward scheme used when training by self-play
+var selfPlayRewards = reward.Scheme{Win: 1.0, Draw: 0.5, Loss: -2.0, Step: -0.01}
+
+// benchmark is a fixed opponent that training progress is measured against
+type benchmark struct {
+	name  string
+	agent agent.Agent
+}
+
+// trainAgent trains by self-play, or against the stages of curriculum when it
+// is not nil, learning from rewards, and measures the agent every
+// evalFrequency games. With a curriculum a record is also written at every
+// promotion. seed seeds the curriculum's opponents.
+func trainAgent(name string, trainAgent agent.Agent, iterations, evalFrequency int, benchmarks []benchmark, curriculum *training.Curriculum, rewards reward.Function, metricsPath string, seed int64) {
 	fmt.Printf("Training %s agent...\n", name)
-	
+
+	writer, err := metrics.Create(metricsPath)
+	if err != nil {
+		fmt.Printf("Failed to create metrics files: %v\n", err)
+		return
+	}
+	defer writer.Close()
+
+	var schedule *training.Schedule
+	if curriculum != nil {
+		if schedule, err = curriculum.Start(); err != nil {
+			fmt.Println(err)
+			return
+		}
+		schedule.Seed(seed)
+	}
+
+	start := time.Now()
+	evaluate := func(iteration int) {
+		record := trainingRecord(trainAgent, benchmarks)
+		record.Agent = filepath.Base(metricsPath)
+		record.Iteration = iteration
+		record.ElapsedSeconds = time.Since(start).Seconds()
+		if schedule != nil {
+			record.Stage = schedule.Current().Opponent
+		}
+		if err := writer.Write(record); err != nil {
+			fmt.Printf("Failed to write metrics: %v\n", err)
+		}
+		fmt.Printf("Iteration %d: %s\n", iteration, formatRecord(record))
+	}
+
+	x, o := training.Seats(trainAgent)
 	for i := 0; i < iterations; i++ {
-		// Self-play training
-		evaluateAgents(trainAgent, trainAgent, 1, RewardScheme{
-			win: 1.0, draw: 0.5, loss: -2.0, step: -0.01,
-		})
+		promoted := false
+		if schedule != nil {
+			if promoted = schedule.Play(trainAgent, rewards); promoted {
+				fmt.Printf("Iteration %d: promoted to stage %d, %s\n", i+1, schedule.Stage()+1, schedule.Current())
+			}
+		} else {
+			// Self-play training, with a view of the agent per seat
+			training.Match(x, o, i, rewards)
+		}
 
-		// Periodic evaluation
-		if (i+1) % evalFrequency == 0 {
-			wins, draws, losses := evaluateAgents(trainAgent, benchmark, 100, RewardScheme{})
-			
-			// Create progress bar (30 chars wide)
-			winChars := int(float64(wins) * 0.3)    // Scale to 30 chars total
-			drawChars := int(float64(draws) * 0.3)
-			lossChars := 30 - winChars - drawChars
-
-			fmt.Printf("Iteration %d: [", i+1)
-			// Print wins in green
-			fmt.Printf("\033[32m%s", strings.Repeat("█", winChars))
-			// Print draws in yellow
-			fmt.Printf("\033[33m%s", strings.Repeat("█", drawChars))
-			// Print losses in red
-			fmt.Printf("\033[31m%s\033[0m", strings.Repeat("█", lossChars))
-			fmt.Printf("] W:%d%% D:%d%% L:%d%%\n",
-				wins, draws, losses)
+		// Periodic evaluation, and at every promotion
+		if promoted || (i+1) % evalFrequency == 0 {
+			evaluate(i + 1)
 		}
 	}
 	fmt.Println()
 }
 
-func evaluateModels() {
-	fmt.Println("=== Evaluating Models ===")
-	
-	// Create agents
-	qagent := agent.NewQAgent(1)
-	sarsaAgent := agent.NewSarsaAgent(1)
-	mcAgent := agent.NewMonteCarloAgent(1)
+// trainingRecord measures an agent at the end of an evaluation window. Agents
+// that can switch off exploration play greedily and are also scored against
+// the tablebase: the fraction of positions where they play an optimal move and
+// how much a perfect adversary can exploit them, for each seat.
+func trainingRecord(trainAgent agent.Agent, benchmarks []benchmark) metrics.Record {
+	var record metrics.Record
+	if instrumented, ok := trainAgent.(agent.Instrumented); ok {
+		record.Epsilon = instrumented.Epsilon()
+		record.TableSize = instrumented.TableSize()
+		record.MeanAbsTDError = instrumented.MeanAbsTDError()
+	}
+
+	if evaluating, ok := trainAgent.(agent.EvaluatingAgent); ok {
+		evaluating.SetEvaluating(true)
+		defer evaluating.SetEvaluating(false)
+
+		// Each seat is scored through its self-play view, which reads
+		// positions the way that seat learned them
+		x, o := training.Seats(trainAgent)
+		tb := solver.Default()
+		record.Greedy = true
+		record.AccuracyX = tb.Accuracy(x.GetMove, 1)
+		record.AccuracyO = tb.Accuracy(o.GetMove, 2)
+		record.ExploitabilityX = solver.Exploitability(x.GetMove, 1)
+		record.ExploitabilityO = solver.Exploitability(o.GetMove, 2)
+	}
 
-	// Load trained models
-	if err := qagent.Load("models/qagent"); err != nil {
-		fmt.Println("No trained Q-Learning model found")
+	for _, b := range benchmarks {
+		wins, draws, losses := training.Play(trainAgent, b.agent, 100, nil)
+		record.Results = append(record.Results, metrics.Result{
+			Opponent: b.name, Wins: wins, Draws: draws, Losses: losses,
+		})
 	}
-	if err := sarsaAgent.Load("models/sarsa"); err != nil {
-		fmt.Println("No trained SARSA model found")
+	return record
+}
+
+// formatRecord summarizes a record on one line for the console
+func formatRecord(record metrics.Record) string {
+	var parts []string
+	if record.Greedy {
+		parts = append(parts, fmt.Sprintf("accuracy X %.1f%% O %.1f%%, exploitability X %.2f O %.2f",
+			record.AccuracyX*100, record.AccuracyO*100, record.ExploitabilityX, record.ExploitabilityO))
 	}
-	if err := mcAgent.Load("models/montecarlo"); err != nil {
-		fmt.Println("No trained Monte Carlo model found")
+	for _, result := range record.Results {
+		games := float64(result.Games())
+		parts = append(parts, fmt.Sprintf("vs %s W:%.0f%% D:%.0f%% L:%.0f%%", result.Opponent,
+			float64(result.Wins)/games*100, float64(result.Draws)/games*100, float64(result.Losses)/games*100))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// evaluateModels evaluates the agents built from specs, or when there are none
+// the model of every registered type saved as models/<type> by -train,
+// -selfplay and -evolve
+func evaluateModels(specs []string) {
+	fmt.Println("=== Evaluating Models ===")
+	if len(specs) == 0 {
+		for _, r := range agent.Registered() {
+			if r.Extension != "" {
+				specs = append(specs, r.Name+":"+filepath.Join("models", r.Name))
+			}
+		}
 	}
 
 	// Number of games for evaluation
@@ -247,69 +509,225 @@ func evaluateModels() {
 	minimax := agent.NewMinimaxAgent(2)
 
 	// Evaluate each agent against random and minimax
-	evaluateAgent("Q-Learning", qagent, random, minimax, numGames)
-	evaluateAgent("SARSA", sarsaAgent, random, minimax, numGames)
-	evaluateAgent("Monte Carlo", mcAgent, random, minimax, numGames)
+	for _, spec := range specs {
+		testAgent, err := agent.New(spec, 1)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		evaluateAgent(agentTitle(spec), testAgent, random, minimax, numGames)
+	}
+}
+
+// agentTitle names the agent of a spec for display: the registered title for
+// a bare name or a saved model of its type in models/, or the spec itself
+func agentTitle(spec string) string {
+	name, model, _ := strings.Cut(spec, ":")
+	r, ok := agent.Lookup(name)
+	if ok && (model == "" || model == filepath.Join("models", r.Name)) {
+		return r.Title
+	}
+	return spec
 }
 
 func evaluateAgent(name string, testAgent, random, minimax agent.Agent, numGames int) {
 	fmt.Printf("\nEvaluating %s agent:\n", name)
+	playGreedily(testAgent)
 	
 	// vs Random
-	wins, draws, losses := evaluateAgents(testAgent, random, numGames, RewardScheme{})
-	fmt.Printf("vs Random:  Win = %.1f%%, Draw = %.1f%%, Loss = %.1f%%\n",
-		float64(wins)/float64(numGames)*100,
-		float64(draws)/float64(numGames)*100,
-		float64(losses)/float64(numGames)*100)
+	wins, draws, losses := training.Play(testAgent, random, numGames, nil)
+	fmt.Printf("vs Random:  %s\n", formatResults(wins, draws, losses))
 
 	// vs Minimax
-	wins, draws, losses = evaluateAgents(testAgent, minimax, numGames, RewardScheme{})
-	fmt.Printf("vs Minimax: Win = %.1f%%, Draw = %.1f%%, Loss = %.1f%%\n",
-		float64(wins)/float64(numGames)*100,
-		float64(draws)/float64(numGames)*100,
-		float64(losses)/float64(numGames)*100)
+	wins, draws, losses = training.Play(testAgent, minimax, numGames, nil)
+	fmt.Printf("vs Minimax: %s\n", formatResults(wins, draws, losses))
 }
 
-func playGame() {
-	fmt.Println("=== Play Against AI ===")
-	
-	// Create agents
-	agents := map[string]agent.Agent{
-		"Random":      agent.NewRandomAgent(1),
-		"Minimax":     agent.NewMinimaxAgent(1),
-		"Q-Learning":  agent.NewQAgent(1),
-		"SARSA":       agent.NewSarsaAgent(1),
-		"Monte Carlo": agent.NewMonteCarloAgent(1),
+// formatResults shows win, draw and loss rates with 95% Wilson intervals, and
+// the match score with a bootstrap interval and its Elo equivalent
+func formatResults(wins, draws, losses int) string {
+	n := wins + draws + losses
+	rate := func(count int) string {
+		interval := stats.Wilson(count, n, 0.95)
+		return fmt.Sprintf("%.1f%% [%.1f, %.1f]", float64(count)/float64(n)*100, interval.Low*100, interval.High*100)
 	}
+	score := stats.Score(wins, draws, losses)
+	interval := stats.Bootstrap(wins, draws, losses, 0.95, 1000, rand.New(rand.NewSource(1)))
+	return fmt.Sprintf("Win = %s, Draw = %s, Loss = %s\n            Score = %.3f %v, Elo %+.0f [%+.0f, %+.0f]",
+		rate(wins), rate(draws), rate(losses),
+		score, interval, stats.Elo(score), stats.Elo(interval.Low), stats.Elo(interval.High))
+}
 
-	for {
-		fmt.Println("\nChoose your opponent:")
-		options := make([]string, 0, len(agents))
-		for name := range agents {
-			options = append(options, name)
+// runSPRT plays two agents against each other until the sequential test
+// decides whether the first is stronger. Agents given by name use their most
+// recent model in models/; others are agent.New specs. Learning agents play
+// greedily.
+func runSPRT(names string, test stats.SPRT, maxGames int) {
+	// Settings after a spec's first one belong to that spec, so
+	// "dqn:seed=1,model=models/dqn,minimax" is two agents
+	var parts []string
+	for _, item := range strings.Split(names, ",") {
+		if len(parts) > 0 && strings.Contains(item, "=") && !strings.Contains(item, ":") {
+			parts[len(parts)-1] += "," + item
+		} else {
+			parts = append(parts, item)
 		}
-		for i, name := range options {
-			fmt.Printf("%d. %s\n", i+1, name)
+	}
+	if len(parts) != 2 {
+		fmt.Println("-sprt needs two agent names separated by a comma, e.g. -sprt Q-Learning,SARSA")
+		return
+	}
+	if err := test.Validate(); err != nil {
+		fmt.Println("Invalid SPRT settings:", err)
+		return
+	}
+
+	var agents [2]agent.Agent
+	for i, name := range parts {
+		var err error
+		if strings.Contains(name, ":") {
+			agents[i], err = agent.New(name, i+1)
+		} else {
+			agents[i], err = latestModel(name, i+1)
 		}
-		fmt.Printf("%d. Exit\n", len(options)+1)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if evaluating, ok := agents[i].(agent.EvaluatingAgent); ok {
+			evaluating.SetEvaluating(true)
+		}
+	}
 
-		var choice int
-		fmt.Print("Enter your choice: ")
-		fmt.Scan(&choice)
+	lower, upper := test.Bounds()
+	fmt.Printf("=== SPRT: %s vs %s ===\n", parts[0], parts[1])
+	fmt.Printf("H0: Elo %+.0f, H1: Elo %+.0f, alpha %.2f, beta %.2f, LLR bounds [%.2f, %.2f]\n",
+		test.Elo0, test.Elo1, test.Alpha, test.Beta, lower, upper)
 
-		if choice == len(options)+1 {
-			fmt.Println("Thanks for playing!")
-			break
+	var wins, draws, losses int
+	play := func(games int) (int, int, int) {
+		w, d, l := training.Play(agents[0], agents[1], games, nil)
+		wins, draws, losses = wins+w, draws+d, losses+l
+		fmt.Printf("Games %d: +%d =%d -%d, LLR %.2f\n", wins+draws+losses, wins, draws, losses,
+			test.LLR(wins, draws, losses))
+		return w, d, l
+	}
+	result := test.Run(play, 100, maxGames)
+
+	fmt.Println(formatResults(result.Wins, result.Draws, result.Losses))
+	switch result.Decision {
+	case stats.AcceptH1:
+		fmt.Printf("%s is stronger than %s\n", parts[0], parts[1])
+	case stats.AcceptH0:
+		fmt.Printf("%s is not stronger than %s\n", parts[0], parts[1])
+	default:
+		fmt.Printf("No decision after %d games\n", maxGames)
+	}
+}
+
+// latestModel builds the agent called name for player from the last model
+// file of its type in models/, or untrained if there is none
+func latestModel(name string, player int) (agent.Agent, error) {
+	r, ok := agent.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown agent %q, expected one of %s", name, strings.Join(agent.Names(), ", "))
+	}
+	if r.Extension == "" {
+		return r.New(player, agent.NewParams(nil))
+	}
+	files, _ := filepath.Glob(filepath.Join("models", "*"+r.Extension))
+	sort.Strings(files)
+	if len(files) == 0 {
+		fmt.Printf("No trained %s model found in models/, using an untrained agent\n", r.Title)
+		return r.New(player, agent.NewParams(nil))
+	}
+	return r.Open(player, files[len(files)-1], agent.NewParams(nil))
+}
+
+// markName shows players 1 and 2 as X and O, with the number the board prints
+func markName(player int) string {
+	if player == 1 {
+		return "X (1)"
+	}
+	return "O (2)"
+}
+
+// playGame lets the player pick an opponent from the registered agents and
+// play it, or plays the agent of the first spec if one is given
+func playGame(specs []string) {
+	fmt.Println("=== Play Against AI ===")
+
+	registered := agent.Registered()
+	for {
+		var selected agent.Registration
+		if len(specs) > 0 {
+			selected.Title = agentTitle(specs[0])
+		} else {
+			fmt.Println("\nChoose your opponent:")
+			for i, r := range registered {
+				fmt.Printf("%d. %s\n", i+1, r.Title)
+			}
+			fmt.Printf("%d. Exit\n", len(registered)+1)
+
+			var choice int
+			fmt.Print("Enter your choice: ")
+			fmt.Scan(&choice)
+
+			if choice == len(registered)+1 {
+				fmt.Println("Thanks for playing!")
+				break
+			}
+
+			if choice < 1 || choice > len(registered) {
+				fmt.Println("Invalid choice, please try again")
+				continue
+			}
+			selected = registered[choice-1]
 		}
 
-		if choice < 1 || choice > len(options) {
-			fmt.Println("Invalid choice, please try again")
+		var side string
+		fmt.Print("Play as X or O? (x/o): ")
+		fmt.Scan(&side)
+		humanPlayer := 1
+		if strings.EqualFold(side, "o") {
+			humanPlayer = 2
+		}
+		agentPlayer := humanPlayer%2 + 1
+
+		var first string
+		fmt.Print("Do you want to move first? (y/n): ")
+		fmt.Scan(&first)
+		humanFirst := strings.EqualFold(first, "y")
+
+		var opponentAgent agent.Agent
+		var err error
+		if len(specs) > 0 {
+			opponentAgent, err = agent.New(specs[0], agentPlayer)
+		} else {
+			opponentAgent, err = openOpponent(selected, agentPlayer)
+		}
+		if err != nil {
+			fmt.Println("Failed to create opponent:", err)
 			continue
 		}
+		playGreedily(opponentAgent)
 
-		agentName := options[choice-1]
-		fmt.Printf("\nPlaying against %s agent\n", agentName)
-		playAgainstAgent(agents[agentName])
+		fmt.Printf("\nPlaying against %s agent. You are %s, the agent is %s.\n",
+			selected.Title, markName(humanPlayer), markName(agentPlayer))
+		if tui.IsTerminal(os.Stdin) {
+			screen := &tui.Game{
+				Opponent:    opponentAgent,
+				HumanPlayer: humanPlayer,
+				HumanFirst:  humanFirst,
+				Evaluate:    opponentEvaluation(opponentAgent),
+				Hint:        positionHint(humanPlayer),
+			}
+			if _, err := screen.Run(os.Stdin, os.Stdout); err != nil {
+				playAgainstAgent(opponentAgent, humanPlayer, humanFirst)
+			}
+		} else {
+			playAgainstAgent(opponentAgent, humanPlayer, humanFirst)
+		}
 
 		fmt.Print("\nPlay another game? (y/n): ")
 		var response string
@@ -321,42 +739,113 @@ func playGame() {
 	}
 }
 
-func playAgainstAgent(agent agent.Agent) {
+// playGreedily switches off exploration for agents that support it, so a
+// loaded model plays its learned policy
+func playGreedily(a agent.Agent) {
+	if evaluating, ok := a.(agent.EvaluatingAgent); ok {
+		evaluating.SetEvaluating(true)
+	}
+}
+
+// openOpponent builds the agent of r for player, letting the player pick one
+// of its saved models. The agent stays untrained if none is found or chosen.
+func openOpponent(r agent.Registration, player int) (agent.Agent, error) {
+	if r.Extension == "" {
+		return r.New(player, agent.NewParams(nil))
+	}
+
+	files, _ := filepath.Glob(filepath.Join("models", "*"+r.Extension))
+	sort.Strings(files)
+	if len(files) == 0 {
+		fmt.Println("No trained model found in models/, playing an untrained agent")
+		return r.New(player, agent.NewParams(nil))
+	}
+
+	fmt.Println("\nChoose a trained model:")
+	for i, file := range files {
+		fmt.Printf("%d. %s\n", i+1, file)
+	}
+	fmt.Printf("%d. Untrained\n", len(files)+1)
+
+	var choice int
+	fmt.Print("Enter your choice: ")
+	fmt.Scan(&choice)
+	if choice < 1 || choice > len(files) {
+		fmt.Println("Playing an untrained agent")
+		return r.New(player, agent.NewParams(nil))
+	}
+	return r.Open(player, files[choice-1], agent.NewParams(nil))
+}
+
+// opponentEvaluation describes how the agent sees a position: its best
+// Q-value for agents that keep one, or the rule behind the heuristic's last move
+func opponentEvaluation(opponent agent.Agent) func(board *game.Board, player int) string {
+	return func(board *game.Board, player int) string {
+		if heuristic, ok := opponent.(*agent.HeuristicAgent); ok {
+			return "last rule " + heuristic.LastRule.String()
+		}
+		valued, ok := opponent.(interface{ GetQValues(string) []float64 })
+		moves := board.GetAvailableMoves()
+		if !ok || len(moves) == 0 {
+			return "n/a"
+		}
+		qValues := valued.GetQValues(opponent.GetStateKey(board))
+		best := moves[0]
+		for _, move := range moves[1:] {
+			if qValues[move] > qValues[best] {
+				best = move
+			}
+		}
+		return fmt.Sprintf("Q=%.2f at %d", qValues[best], best+1)
+	}
+}
+
+// positionHint returns a function that builds the analysis table for the
+// human's side, loading the trained models once
+func positionHint(humanPlayer int) func(board *game.Board, player int) string {
+	models := loadAnalysisModels(humanPlayer)
+	return func(board *game.Board, player int) string {
+		return analysisTable(board, player, models)
+	}
+}
+
+func playAgainstAgent(opponent agent.Agent, humanPlayer int, humanFirst bool) {
 	board := game.NewBoard()
+	agentPlayer := humanPlayer%2 + 1
+	humanTurn := humanFirst
+	hint := positionHint(humanPlayer)
+
+	fmt.Printf("\n%s\n", board.String())
 	for {
-		// Agent's turn
-		move := agent.ChooseAction(board)
-		board.MakeMove(move, 1)
-		fmt.Printf("\nAgent plays position %d:\n%s\n", move+1, board.String())
-		
-		gameOver, winner := board.IsGameOver()
-		if gameOver {
-			if winner == 1 {
-				fmt.Println("Agent wins!")
-			} else if winner == 0 {
-				fmt.Println("It's a draw!")
-			} else {
-				fmt.Println("You win!")
+		if humanTurn {
+			for {
+				var input string
+				fmt.Print("Enter your move (1-9) or 'hint': ")
+				fmt.Scan(&input)
+				if input == "hint" {
+					fmt.Printf("\n%s\n", hint(board, humanPlayer))
+					continue
+				}
+				humanMove, err := strconv.Atoi(input)
+				if err == nil && humanMove >= 1 && humanMove <= 9 && board.MakeMove(humanMove-1, humanPlayer) {
+					break
+				}
+				fmt.Println("Invalid move, try again")
 			}
-			break
-		}
-		
-		// Human player's turn
-		var humanMove int
-		for {
-			fmt.Print("Enter your move (1-9): ")
-			fmt.Scan(&humanMove)
-			if humanMove >= 1 && humanMove <= 9 && board.MakeMove(humanMove-1, 2) {
-				break
+			fmt.Printf("\nYour move:\n%s\n", board.String())
+		} else {
+			move := opponent.GetMove(board, agentPlayer)
+			board.MakeMove(move, agentPlayer)
+			fmt.Printf("\nAgent plays position %d", move+1)
+			if heuristic, ok := opponent.(*agent.HeuristicAgent); ok {
+				fmt.Printf(" (%s)", heuristic.LastRule)
 			}
-			fmt.Println("Invalid move, try again")
+			fmt.Printf(":\n%s\n", board.String())
 		}
-		
-		fmt.Printf("\nYour move:\n%s\n", board.String())
-		
-		gameOver, winner = board.IsGameOver()
+
+		gameOver, winner := board.IsGameOver()
 		if gameOver {
-			if winner == 1 {
+			if winner == agentPlayer {
 				fmt.Println("Agent wins!")
 			} else if winner == 0 {
 				fmt.Println("It's a draw!")
@@ -365,59 +854,355 @@ func playAgainstAgent(agent agent.Agent) {
 			}
 			break
 		}
+		humanTurn = !humanTurn
 	}
 }
 
 // Add a function to compare different reward schemes
-func compareRewardSchemes() {
-	schemes := []RewardScheme{
-		{win: 1.0, draw: 0.0, loss: -1.0, step: 0.0},    // Standard
-		{win: 1.0, draw: 0.5, loss: -1.0, step: 0.0},    // Reward draws
-		{win: 2.0, draw: 0.0, loss: -1.0, step: 0.0},    // Emphasize winning
-		{win: 1.0, draw: 0.0, loss: -2.0, step: 0.0},    // Emphasize avoiding losses
-		{win: 1.0, draw: 0.0, loss: -1.0, step: -0.1},   // Penalize long games
+// compareRewardSchemes trains a fresh agent of each type that takes Options
+// under each reward scheme and overlays all of their learning curves on one
+// set of charts in runs/<start time>-reward-schemes
+func compareRewardSchemes(exploreSpec string) {
+	schemes := []reward.Function{
+		reward.Scheme{Win: 1.0, Draw: 0.0, Loss: -1.0},             // Standard
+		reward.Scheme{Win: 1.0, Draw: 0.5, Loss: -1.0},             // Reward draws
+		reward.Scheme{Win: 2.0, Draw: 0.0, Loss: -1.0},             // Emphasize winning
+		reward.Scheme{Win: 1.0, Draw: 0.0, Loss: -2.0},             // Emphasize avoiding losses
+		reward.Scheme{Win: 1.0, Draw: 0.0, Loss: -1.0, Step: -0.1}, // Penalize long games
+		reward.Shaped{ // Reward creating and blocking threats
+			Base:      reward.Sparse(),
+			Potential: reward.Threats(0.1),
+			Gamma:     agent.DefaultOptions().Gamma,
+		},
 	}
+	benchmarks := []benchmark{{"random", agent.NewRandomAgent(2)}}
 
+	var runs []plot.Run
 	for i, scheme := range schemes {
 		fmt.Printf("\n=== Testing Reward Scheme %d ===\n", i+1)
-		fmt.Printf("Win: %.1f, Draw: %.1f, Loss: %.1f, Step: %.1f\n", 
-			scheme.win, scheme.draw, scheme.loss, scheme.step)
+		fmt.Println(scheme)
 
 		// Create fresh agents for each scheme
-		qagent := agent.NewQAgent(1)
-		sarsaAgent := agent.NewSarsaAgent(1)
-		mcAgent := agent.NewMonteCarloAgent(1)
-		randomAgent := agent.NewRandomAgent(2)
+		registrations := optionsAgents()
+		agents := make([]agent.Agent, len(registrations))
+		schemeRuns := make([]plot.Run, len(registrations))
+		for k, r := range registrations {
+			newAgent, _, _ := optionsAgent(r.Name)
+			agents[k] = newAgent(agent.DefaultOptions())
+			setExplorers(exploreSpec, agents[k])
+			schemeRuns[k].Name = fmt.Sprintf("%s-scheme%d", r.Name, i+1)
+		}
 
 		// Train agents
 		for j := 0; j < 5; j++ {
-			// Training phase
-			evaluateAgents(qagent, qagent, 1000, scheme)
-			evaluateAgents(sarsaAgent, sarsaAgent, 1000, scheme)
-			evaluateAgents(mcAgent, mcAgent, 1000, scheme)
-
-			// Evaluation phase against random agent
 			fmt.Printf("\nAfter %d iterations:\n", (j+1)*1000)
-			
-			qagent.Evaluating = true
-			sarsaAgent.Evaluating = true
-			mcAgent.Evaluating = true
-			
-			wins, draws, _ := evaluateAgents(qagent, randomAgent, 200, scheme)
-			fmt.Printf("Q-Learning - Win: %.1f%%, Draw: %.1f%%\n", 
-				float64(wins)/2.0, float64(draws)/2.0)
-			
-			wins, draws, _ = evaluateAgents(sarsaAgent, randomAgent, 200, scheme)
-			fmt.Printf("SARSA      - Win: %.1f%%, Draw: %.1f%%\n", 
-				float64(wins)/2.0, float64(draws)/2.0)
-			
-			wins, draws, _ = evaluateAgents(mcAgent, randomAgent, 200, scheme)
-			fmt.Printf("Monte Carlo - Win: %.1f%%, Draw: %.1f%%\n", 
-				float64(wins)/2.0, float64(draws)/2.0)
-			
-			qagent.Evaluating = false
-			sarsaAgent.Evaluating = false
-			mcAgent.Evaluating = false
-		}
-	}
-} 
+			for k, a := range agents {
+				training.SelfPlay(a, 1000, scheme)
+
+				record := trainingRecord(a, benchmarks)
+				record.Agent = schemeRuns[k].Name
+				record.Iteration = (j + 1) * 1000
+				schemeRuns[k].Records = append(schemeRuns[k].Records, record)
+				fmt.Printf("%-11s %s\n", registrations[k].Name, formatRecord(record))
+			}
+		}
+		runs = append(runs, schemeRuns...)
+	}
+
+	dir := filepath.Join("runs", time.Now().Format("20060102-150405")+"-reward-schemes")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fmt.Println("Failed to create run directory:", err)
+		return
+	}
+	for _, run := range runs {
+		writer, err := metrics.Create(filepath.Join(dir, run.Name))
+		if err != nil {
+			fmt.Printf("Failed to create metrics files: %v\n", err)
+			return
+		}
+		for _, record := range run.Records {
+			writer.Write(record)
+		}
+		writer.Close()
+	}
+	writeCharts(dir, runs)
+}
+
+// setExplorers gives each tabular agent its own explorer built from spec. An
+// empty spec keeps the agents' epsilon-greedy defaults.
+func setExplorers(spec string, agents ...agent.Agent) {
+	if spec == "" {
+		return
+	}
+	for _, a := range agents {
+		exploring, ok := a.(agent.Exploring)
+		if !ok {
+			continue
+		}
+		e, err := agent.ParseExplorer(spec)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		exploring.SetExplorer(e)
+	}
+}
+
+// optionsAgent returns a constructor from Options for the agent type called
+// name, which -sweep searches and -league trains. Only types that take
+// Options qualify.
+func optionsAgent(name string) (func(opts agent.Options) agent.LearningAgent, agent.Registration, error) {
+	r, ok := agent.Lookup(name)
+	if !ok {
+		return nil, r, fmt.Errorf("unknown agent %q, expected one of %s", name, strings.Join(agent.Names(), ", "))
+	}
+	a, err := agent.NewWithParams(r.Name, 1, agent.DefaultOptions().Params())
+	if _, learns := a.(agent.LearningAgent); err != nil || !learns {
+		return nil, r, fmt.Errorf("agent %s has no options to train with", r.Name)
+	}
+	newAgent := func(opts agent.Options) agent.LearningAgent {
+		a, _ := agent.NewWithParams(r.Name, 1, opts.Params())
+		return a.(agent.LearningAgent)
+	}
+	return newAgent, r, nil
+}
+
+// optionsAgents returns every registered agent type that takes Options
+func optionsAgents() []agent.Registration {
+	var registrations []agent.Registration
+	for _, r := range agent.Registered() {
+		if _, _, err := optionsAgent(r.Name); err == nil {
+			registrations = append(registrations, r)
+		}
+	}
+	return registrations
+}
+
+// sweepScore is the mean match score of an agent's greedy policy against the
+// random and minimax benchmarks
+func sweepScore(a agent.Agent) float64 {
+	if evaluating, ok := a.(agent.EvaluatingAgent); ok {
+		evaluating.SetEvaluating(true)
+		defer evaluating.SetEvaluating(false)
+	}
+	numGames := 200
+	score := 0.0
+	for _, opponent := range []agent.Agent{agent.NewRandomAgent(2), agent.NewMinimaxAgent(2)} {
+		score += stats.Score(training.Play(a, opponent, numGames, nil))
+	}
+	return score / 2
+}
+
+// runSweep searches the hyperparameters of one agent type, writes the ranked
+// table to runs/<start time>-sweep-<agent>/results.txt and saves the best
+// model as models/<agent>-sweep. A non-empty exploreSpec replaces the
+// epsilon-greedy schedule, leaving the sweep to search alpha and gamma. seed
+// draws the random candidates, or the clock when it is 0.
+func runSweep(name, search string, trials, games, workers int, exploreSpec string, seed int64) {
+	newAgent, registration, err := optionsAgent(name)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	name = registration.Name
+
+	var candidates []agent.Options
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+	switch search {
+	case "grid":
+		candidates = sweep.Grid(sweep.DefaultSpace())
+	case "random", "halving":
+		candidates = sweep.Random(sweep.DefaultSpace(), trials, rng)
+	default:
+		fmt.Printf("Unknown search %q, expected grid, random or halving\n", search)
+		return
+	}
+
+	runner := sweep.Runner{
+		New: func(opts agent.Options) agent.LearningAgent {
+			a := newAgent(opts)
+			setExplorers(exploreSpec, a)
+			return a
+		},
+		Train: func(a agent.Agent, n int) {
+			training.SelfPlay(a, n, selfPlayRewards)
+		},
+		Score:   sweepScore,
+		Workers: workers,
+	}
+	fmt.Printf("=== Sweeping %s: %s search over %d candidates ===\n", name, search, len(candidates))
+	var results []sweep.Trial
+	if search == "halving" {
+		results = runner.SuccessiveHalving(candidates, games, 2)
+	} else {
+		results = runner.Evaluate(candidates, games)
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No candidates to sweep")
+		return
+	}
+
+	var table strings.Builder
+	sweep.WriteTable(&table, results)
+	fmt.Print(table.String())
+
+	dir := filepath.Join("runs", time.Now().Format("20060102-150405")+"-sweep-"+name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fmt.Println("Failed to create run directory:", err)
+		return
+	}
+	if err := os.WriteFile(filepath.Join(dir, "results.txt"), []byte(table.String()), 0644); err != nil {
+		fmt.Println("Failed to write results:", err)
+	}
+	os.MkdirAll("models", 0755)
+	best := filepath.Join("models", name+"-sweep")
+	if err := results[0].Agent.Save(best); err != nil {
+		fmt.Println("Failed to save best model:", err)
+		return
+	}
+	fmt.Printf("Results written to %s, best model (%v) saved to %s%s\n",
+		dir, results[0].Options, best, registration.Extension)
+}
+
+// runLeague trains one agent type against a league of its own snapshots and
+// the random and minimax agents, resuming the league saved in
+// models/league-<agent> if there is one. The learner is measured after every
+// snapshot and the curve is written to runs/<start time>-league-<agent>.
+// The learner, the random member and the sampling are seeded from seed, or
+// the clock when it is 0.
+func runLeague(name, samplingName string, games int, exploreSpec string, seed int64) {
+	newOptionsAgent, registration, err := optionsAgent(name)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	name = registration.Name
+	sampling, err := league.ParseSampling(samplingName)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	newAgent := func() agent.LearningAgent { return newOptionsAgent(agent.DefaultOptions()) }
+	learner := newAgent()
+	setExplorers(exploreSpec, learner)
+	config := league.DefaultConfig()
+	config.Sampling = sampling
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+	if seeder, ok := learner.(agent.Seeder); ok {
+		seeder.Seed(rng.Int63())
+	}
+	config.Seed = rng.Int63()
+	config.Rewards = selfPlayRewards
+	dir := filepath.Join("models", "league-"+name)
+	l, err := league.Open(dir, learner, newAgent, config)
+	if err != nil {
+		fmt.Println("Failed to open league:", err)
+		return
+	}
+	random := agent.NewRandomAgent(2)
+	random.Seed(rng.Int63())
+	l.AddFixed("random", random)
+	l.AddFixed("minimax", agent.NewMinimaxAgent(2))
+
+	runDir := filepath.Join("runs", time.Now().Format("20060102-150405")+"-league-"+name)
+	if err := os.MkdirAll(runDir, 0755); err != nil {
+		fmt.Println("Failed to create run directory:", err)
+		return
+	}
+	writer, err := metrics.Create(filepath.Join(runDir, name))
+	if err != nil {
+		fmt.Printf("Failed to create metrics files: %v\n", err)
+		return
+	}
+	defer writer.Close()
+
+	fmt.Printf("=== League training %s: %s sampling, resuming at game %d ===\n", name, sampling, l.Games())
+	benchmarks := []benchmark{
+		{"random", agent.NewRandomAgent(2)},
+		{"minimax", agent.NewMinimaxAgent(2)},
+	}
+	start := time.Now()
+	for remaining := games; remaining > 0; {
+		// Train up to the next snapshot, so each record sees a new pool
+		n := config.SnapshotEvery - l.Games()%config.SnapshotEvery
+		if n > remaining {
+			n = remaining
+		}
+		if err := l.Train(n); err != nil {
+			fmt.Println("Failed to save league:", err)
+			return
+		}
+		remaining -= n
+
+		record := trainingRecord(learner, benchmarks)
+		record.Agent = name
+		record.Iteration = l.Games()
+		record.ElapsedSeconds = time.Since(start).Seconds()
+		if err := writer.Write(record); err != nil {
+			fmt.Printf("Failed to write metrics: %v\n", err)
+		}
+		fmt.Printf("Game %d: %s\n", l.Games(), formatRecord(record))
+	}
+
+	fmt.Printf("\n%-18s %8s %8s\n", "Opponent", "Games", "Score")
+	for _, m := range l.Members() {
+		fmt.Printf("%-18s %8d %7.1f%%\n", m.Name, m.Wins+m.Draws+m.Losses, m.Score()*100)
+	}
+	fmt.Printf("League saved to %s, metrics written to %s\n", dir, runDir)
+}
+
+// writeCharts renders the standard learning-curve charts for runs into dir
+func writeCharts(dir string, runs []plot.Run) {
+	written, err := plot.WriteCharts(dir, runs)
+	if err != nil {
+		fmt.Printf("Failed to write charts: %v\n", err)
+	}
+	fmt.Printf("Wrote %d charts (SVG and PNG) to %s\n", len(written), dir)
+}
+
+// plotRuns renders charts from metrics files. Each argument is a .jsonl file
+// or a directory of them; all runs are overlaid and the charts are written to
+// the directory of the first one. Runs from different directories are named
+// after their directory as well, so they stay apart in the legend.
+func plotRuns(paths []string) {
+	var files []string
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			matches, _ := filepath.Glob(filepath.Join(path, "*.jsonl"))
+			sort.Strings(matches)
+			files = append(files, matches...)
+		} else {
+			files = append(files, path)
+		}
+	}
+	if len(files) == 0 {
+		fmt.Println("No metrics files found")
+		return
+	}
+
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		dirs[filepath.Dir(file)] = true
+	}
+	var runs []plot.Run
+	for _, file := range files {
+		run, err := plot.LoadRun(file)
+		if err != nil {
+			fmt.Printf("Failed to read %s: %v\n", file, err)
+			return
+		}
+		if len(dirs) > 1 {
+			run.Name = filepath.Base(filepath.Dir(file)) + "/" + run.Name
+		}
+		runs = append(runs, run)
+	}
+	writeCharts(filepath.Dir(files[0]), runs)
+}
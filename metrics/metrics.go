@@ -0,0 +1,161 @@
+// Package metrics records learning curves: one Record per evaluation window,
+// written to a CSV file and a JSON-lines file side by side.
+package metrics
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Result is the win/draw/loss record against one benchmark opponent
+type Result struct {
+	Opponent string `json:"opponent"`
+	Wins     int    `json:"wins"`
+	Draws    int    `json:"draws"`
+	Losses   int    `json:"losses"`
+}
+
+// Games returns the number of games played
+func (r Result) Games() int {
+	return r.Wins + r.Draws + r.Losses
+}
+
+// Record is one evaluation window of a training run. The accuracy and
+// exploitability fields are only meaningful when Greedy is set, i.e. the
+// agent could switch off exploration for scoring. Stage names the curriculum
+// stage the agent was training in, if any; a record is also written at each
+// promotion, so a change of Stage marks the game it happened at.
+type Record struct {
+	Agent           string   `json:"agent"`
+	Iteration       int      `json:"iteration"`
+	Epsilon         float64  `json:"epsilon"`
+	TableSize       int      `json:"table_size"`
+	MeanAbsTDError  float64  `json:"mean_abs_td_error"`
+	Greedy          bool     `json:"greedy"`
+	AccuracyX       float64  `json:"accuracy_x"`
+	AccuracyO       float64  `json:"accuracy_o"`
+	ExploitabilityX float64  `json:"exploitability_x"`
+	ExploitabilityO float64  `json:"exploitability_o"`
+	Results         []Result `json:"results"`
+	ElapsedSeconds  float64  `json:"elapsed_seconds"`
+	Stage           string   `json:"stage,omitempty"`
+}
+
+// Writer appends records to <path>.csv and <path>.jsonl. The CSV columns for
+// benchmark results are fixed by the first record written.
+type Writer struct {
+	csvFile   *os.File
+	jsonFile  *os.File
+	csv       *csv.Writer
+	json      *json.Encoder
+	opponents []string
+}
+
+// Create creates (or truncates) <path>.csv and <path>.jsonl
+func Create(path string) (*Writer, error) {
+	csvFile, err := os.Create(path + ".csv")
+	if err != nil {
+		return nil, err
+	}
+	jsonFile, err := os.Create(path + ".jsonl")
+	if err != nil {
+		csvFile.Close()
+		return nil, err
+	}
+	return &Writer{
+		csvFile:  csvFile,
+		jsonFile: jsonFile,
+		csv:      csv.NewWriter(csvFile),
+		json:     json.NewEncoder(jsonFile),
+	}, nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', 6, 64)
+}
+
+// Write appends one record to both files
+func (w *Writer) Write(r Record) error {
+	if w.opponents == nil {
+		header := []string{"agent", "iteration", "epsilon", "table_size", "mean_abs_td_error",
+			"accuracy_x", "accuracy_o", "exploitability_x", "exploitability_o"}
+		w.opponents = []string{}
+		for _, result := range r.Results {
+			w.opponents = append(w.opponents, result.Opponent)
+			header = append(header, result.Opponent+"_wins", result.Opponent+"_draws", result.Opponent+"_losses")
+		}
+		header = append(header, "elapsed_seconds", "stage")
+		if err := w.csv.Write(header); err != nil {
+			return err
+		}
+	}
+	if len(r.Results) != len(w.opponents) {
+		return fmt.Errorf("record has %d benchmark results, want %d", len(r.Results), len(w.opponents))
+	}
+
+	// Scores that were not measured are left empty rather than written as 0
+	scores := make([]string, 4)
+	if r.Greedy {
+		scores = []string{formatFloat(r.AccuracyX), formatFloat(r.AccuracyO),
+			formatFloat(r.ExploitabilityX), formatFloat(r.ExploitabilityO)}
+	}
+	row := []string{r.Agent, strconv.Itoa(r.Iteration), formatFloat(r.Epsilon),
+		strconv.Itoa(r.TableSize), formatFloat(r.MeanAbsTDError)}
+	row = append(row, scores...)
+	for i, result := range r.Results {
+		if result.Opponent != w.opponents[i] {
+			return fmt.Errorf("benchmark %d is %q, want %q", i, result.Opponent, w.opponents[i])
+		}
+		row = append(row, strconv.Itoa(result.Wins), strconv.Itoa(result.Draws), strconv.Itoa(result.Losses))
+	}
+	row = append(row, formatFloat(r.ElapsedSeconds), r.Stage)
+	if err := w.csv.Write(row); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	return w.json.Encode(r)
+}
+
+// Close flushes and closes both files
+func (w *Writer) Close() error {
+	w.csv.Flush()
+	csvErr := w.csv.Error()
+	if err := w.csvFile.Close(); csvErr == nil {
+		csvErr = err
+	}
+	jsonErr := w.jsonFile.Close()
+	if csvErr != nil {
+		return csvErr
+	}
+	return jsonErr
+}
+
+// ReadJSONL reads every record from a JSON-lines file written by Writer
+func ReadJSONL(filename string) ([]Record, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", filename, line, err)
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
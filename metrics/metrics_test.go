@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func testRecords() []Record {
+	return []Record{
+		{
+			Agent:          "qlearning",
+			Iteration:      5000,
+			Epsilon:        0.75,
+			TableSize:      4200,
+			MeanAbsTDError: 0.125,
+			Greedy:         true,
+			AccuracyX:      0.6,
+			AccuracyO:      0.5,
+			Results: []Result{
+				{Opponent: "random", Wins: 70, Draws: 10, Losses: 20},
+				{Opponent: "minimax", Wins: 0, Draws: 40, Losses: 60},
+			},
+			ElapsedSeconds: 1.5,
+			Stage:          "random",
+		},
+		{
+			Agent:     "qlearning",
+			Iteration: 10000,
+			Results: []Result{
+				{Opponent: "random", Wins: 80, Draws: 10, Losses: 10},
+				{Opponent: "minimax", Wins: 0, Draws: 55, Losses: 45},
+			},
+			ElapsedSeconds: 3,
+		},
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "qlearning")
+	w, err := Create(path)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, r := range testRecords() {
+		if err := w.Write(r); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	got, err := ReadJSONL(path + ".jsonl")
+	if err != nil {
+		t.Fatalf("ReadJSONL() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, testRecords()) {
+		t.Errorf("ReadJSONL() = %+v, want %+v", got, testRecords())
+	}
+
+	file, err := os.Open(path + ".csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("CSV has %d rows, want header plus 2", len(rows))
+	}
+	wantHeader := []string{"agent", "iteration", "epsilon", "table_size", "mean_abs_td_error",
+		"accuracy_x", "accuracy_o", "exploitability_x", "exploitability_o",
+		"random_wins", "random_draws", "random_losses",
+		"minimax_wins", "minimax_draws", "minimax_losses", "elapsed_seconds", "stage"}
+	if !reflect.DeepEqual(rows[0], wantHeader) {
+		t.Errorf("CSV header = %v, want %v", rows[0], wantHeader)
+	}
+	if rows[1][5] != "0.6" || rows[1][9] != "70" || rows[1][15] != "1.5" || rows[1][16] != "random" {
+		t.Errorf("CSV row = %v", rows[1])
+	}
+	if rows[2][5] != "" {
+		t.Errorf("accuracy for a non-greedy record = %q, want empty", rows[2][5])
+	}
+}
+
+func TestWriterRejectsChangedBenchmarks(t *testing.T) {
+	w, err := Create(filepath.Join(t.TempDir(), "run"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	records := testRecords()
+	if err := w.Write(records[0]); err != nil {
+		t.Fatal(err)
+	}
+	records[1].Results = records[1].Results[:1]
+	if err := w.Write(records[1]); err == nil {
+		t.Errorf("Write() accepted a record with a different set of benchmarks")
+	}
+}
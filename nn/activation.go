@@ -0,0 +1,38 @@
+package nn
+
+import "math"
+
+// Activation names the nonlinearity applied to a layer's output
+type Activation string
+
+const (
+	Linear Activation = "linear"
+	ReLU   Activation = "relu"
+	Tanh   Activation = "tanh"
+)
+
+func (a Activation) apply(x float64) float64 {
+	switch a {
+	case ReLU:
+		return math.Max(0, x)
+	case Tanh:
+		return math.Tanh(x)
+	default:
+		return x
+	}
+}
+
+// derivative returns the slope of the activation given its output y
+func (a Activation) derivative(y float64) float64 {
+	switch a {
+	case ReLU:
+		if y > 0 {
+			return 1
+		}
+		return 0
+	case Tanh:
+		return 1 - y*y
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,91 @@
+package nn
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Dense is a fully connected layer: output = activation(Weights * input + Biases)
+type Dense struct {
+	Weights    [][]float64 `json:"weights"`
+	Biases     []float64   `json:"biases"`
+	Activation Activation  `json:"activation"`
+
+	input       []float64
+	output      []float64
+	weightGrads [][]float64
+	biasGrads   []float64
+}
+
+// NewDense creates a layer with weights drawn from rng, scaled by fan-in
+func NewDense(inputs, outputs int, activation Activation, rng *rand.Rand) *Dense {
+	scale := math.Sqrt(1 / float64(inputs))
+	if activation == ReLU {
+		scale = math.Sqrt(2 / float64(inputs))
+	}
+
+	d := &Dense{
+		Weights:    make([][]float64, outputs),
+		Biases:     make([]float64, outputs),
+		Activation: activation,
+	}
+	for o := range d.Weights {
+		d.Weights[o] = make([]float64, inputs)
+		for i := range d.Weights[o] {
+			d.Weights[o][i] = rng.NormFloat64() * scale
+		}
+	}
+	return d
+}
+
+func (d *Dense) Inputs() int {
+	return len(d.Weights[0])
+}
+
+func (d *Dense) Outputs() int {
+	return len(d.Weights)
+}
+
+// Forward computes the layer output and remembers it for Backward
+func (d *Dense) Forward(input []float64) []float64 {
+	d.input = input
+	d.output = make([]float64, len(d.Weights))
+	for o, row := range d.Weights {
+		sum := d.Biases[o]
+		for i, w := range row {
+			sum += w * input[i]
+		}
+		d.output[o] = d.Activation.apply(sum)
+	}
+	return d.output
+}
+
+// Backward accumulates gradients for the last Forward call and returns the
+// gradient with respect to the layer input
+func (d *Dense) Backward(outputGrad []float64) []float64 {
+	if d.weightGrads == nil {
+		d.zeroGrads()
+	}
+
+	inputGrad := make([]float64, len(d.input))
+	for o, row := range d.Weights {
+		delta := outputGrad[o] * d.Activation.derivative(d.output[o])
+		if delta == 0 {
+			continue
+		}
+		d.biasGrads[o] += delta
+		for i, w := range row {
+			d.weightGrads[o][i] += delta * d.input[i]
+			inputGrad[i] += w * delta
+		}
+	}
+	return inputGrad
+}
+
+func (d *Dense) zeroGrads() {
+	d.weightGrads = make([][]float64, len(d.Weights))
+	for o := range d.Weights {
+		d.weightGrads[o] = make([]float64, len(d.Weights[o]))
+	}
+	d.biasGrads = make([]float64, len(d.Biases))
+}
@@ -0,0 +1,59 @@
+package nn
+
+import "math"
+
+// Loss measures how far a prediction is from its target
+type Loss interface {
+	// Loss returns the loss summed over all outputs
+	Loss(prediction, target []float64) float64
+
+	// Gradient returns the derivative of the loss with respect to each prediction
+	Gradient(prediction, target []float64) []float64
+}
+
+// MSE is half the squared error, so its gradient is simply prediction - target
+type MSE struct{}
+
+func (MSE) Loss(prediction, target []float64) float64 {
+	sum := 0.0
+	for i := range prediction {
+		diff := prediction[i] - target[i]
+		sum += 0.5 * diff * diff
+	}
+	return sum
+}
+
+func (MSE) Gradient(prediction, target []float64) []float64 {
+	grad := make([]float64, len(prediction))
+	for i := range prediction {
+		grad[i] = prediction[i] - target[i]
+	}
+	return grad
+}
+
+// Huber is quadratic for errors smaller than Delta and linear beyond it
+type Huber struct {
+	Delta float64
+}
+
+func (h Huber) Loss(prediction, target []float64) float64 {
+	sum := 0.0
+	for i := range prediction {
+		diff := math.Abs(prediction[i] - target[i])
+		if diff <= h.Delta {
+			sum += 0.5 * diff * diff
+		} else {
+			sum += h.Delta * (diff - 0.5*h.Delta)
+		}
+	}
+	return sum
+}
+
+func (h Huber) Gradient(prediction, target []float64) []float64 {
+	grad := make([]float64, len(prediction))
+	for i := range prediction {
+		diff := prediction[i] - target[i]
+		grad[i] = math.Max(-h.Delta, math.Min(h.Delta, diff))
+	}
+	return grad
+}
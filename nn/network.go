@@ -0,0 +1,112 @@
+// Package nn is a small dependency-free feed-forward neural network library.
+// Networks run on the CPU only and are deterministic for a given random source.
+package nn
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"os"
+)
+
+// Network is a stack of dense layers
+type Network struct {
+	Layers []*Dense `json:"layers"`
+}
+
+// NewNetwork builds a network with the given layer sizes, e.g. []int{18, 64, 9}.
+// Hidden layers use the hidden activation and the last layer uses the output activation.
+func NewNetwork(sizes []int, hidden, output Activation, rng *rand.Rand) *Network {
+	net := &Network{}
+	for i := 0; i < len(sizes)-1; i++ {
+		activation := hidden
+		if i == len(sizes)-2 {
+			activation = output
+		}
+		net.Layers = append(net.Layers, NewDense(sizes[i], sizes[i+1], activation, rng))
+	}
+	return net
+}
+
+// Predict runs a forward pass
+func (n *Network) Predict(input []float64) []float64 {
+	out := input
+	for _, layer := range n.Layers {
+		out = layer.Forward(out)
+	}
+	return out
+}
+
+// Backward propagates the loss gradient of the last Predict call through every layer
+func (n *Network) Backward(grad []float64) {
+	for i := len(n.Layers) - 1; i >= 0; i-- {
+		grad = n.Layers[i].Backward(grad)
+	}
+}
+
+// TrainBatch runs one optimizer step over a batch and returns the mean loss
+func (n *Network) TrainBatch(inputs, targets [][]float64, loss Loss, opt Optimizer) float64 {
+	total := 0.0
+	for i := range inputs {
+		prediction := n.Predict(inputs[i])
+		total += loss.Loss(prediction, targets[i])
+		n.Backward(loss.Gradient(prediction, targets[i]))
+	}
+	opt.Step(n, len(inputs))
+	return total / float64(len(inputs))
+}
+
+// Clone returns a deep copy of the network weights
+func (n *Network) Clone() *Network {
+	clone := &Network{}
+	for _, layer := range n.Layers {
+		copied := &Dense{
+			Weights:    make([][]float64, len(layer.Weights)),
+			Biases:     append([]float64(nil), layer.Biases...),
+			Activation: layer.Activation,
+		}
+		for o := range layer.Weights {
+			copied.Weights[o] = append([]float64(nil), layer.Weights[o]...)
+		}
+		clone.Layers = append(clone.Layers, copied)
+	}
+	return clone
+}
+
+// CopyFrom overwrites this network's weights with those of another network
+// of the same shape
+func (n *Network) CopyFrom(other *Network) {
+	for l, layer := range other.Layers {
+		for o := range layer.Weights {
+			copy(n.Layers[l].Weights[o], layer.Weights[o])
+		}
+		copy(n.Layers[l].Biases, layer.Biases)
+	}
+}
+
+func (n *Network) Save(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return json.NewEncoder(file).Encode(n)
+}
+
+func Load(filename string) (*Network, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var net Network
+	if err := json.NewDecoder(file).Decode(&net); err != nil {
+		return nil, err
+	}
+	if len(net.Layers) == 0 {
+		return nil, errors.New("network has no layers")
+	}
+	return &net, nil
+}
@@ -0,0 +1,102 @@
+package nn
+
+import (
+	"math"
+	"math/rand"
+	"path/filepath"
+	"testing"
+)
+
+var xorInputs = [][]float64{{0, 0}, {0, 1}, {1, 0}, {1, 1}}
+var xorTargets = [][]float64{{0}, {1}, {1}, {0}}
+
+func TestTrainXOR(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Optimizer
+		loss Loss
+	}{
+		{name: "sgd mse", opt: &SGD{LearningRate: 0.5}, loss: MSE{}},
+		{name: "adam mse", opt: NewAdam(0.05), loss: MSE{}},
+		{name: "adam huber", opt: NewAdam(0.05), loss: Huber{Delta: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			net := NewNetwork([]int{2, 8, 1}, Tanh, Linear, rand.New(rand.NewSource(1)))
+			for i := 0; i < 2000; i++ {
+				net.TrainBatch(xorInputs, xorTargets, tt.loss, tt.opt)
+			}
+
+			for i, input := range xorInputs {
+				if got := net.Predict(input)[0]; math.Abs(got-xorTargets[i][0]) > 0.1 {
+					t.Errorf("Predict(%v) = %v, want %v", input, got, xorTargets[i][0])
+				}
+			}
+		})
+	}
+}
+
+func TestGradient(t *testing.T) {
+	net := NewNetwork([]int{3, 4, 2}, ReLU, Tanh, rand.New(rand.NewSource(2)))
+	input := []float64{0.5, -0.3, 0.8}
+	target := []float64{0.2, -0.4}
+	loss := MSE{}
+
+	net.Backward(loss.Gradient(net.Predict(input), target))
+
+	// Compare each analytic weight gradient against a central difference
+	const h = 1e-6
+	for l, layer := range net.Layers {
+		for o := range layer.Weights {
+			for i := range layer.Weights[o] {
+				w := layer.Weights[o][i]
+				layer.Weights[o][i] = w + h
+				plus := loss.Loss(net.Predict(input), target)
+				layer.Weights[o][i] = w - h
+				minus := loss.Loss(net.Predict(input), target)
+				layer.Weights[o][i] = w
+
+				numeric := (plus - minus) / (2 * h)
+				if math.Abs(numeric-layer.weightGrads[o][i]) > 1e-5 {
+					t.Errorf("layer %d weight [%d][%d] gradient = %v, want %v",
+						l, o, i, layer.weightGrads[o][i], numeric)
+				}
+			}
+		}
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	train := func() []float64 {
+		net := NewNetwork([]int{2, 4, 1}, ReLU, Linear, rand.New(rand.NewSource(7)))
+		opt := NewAdam(0.01)
+		for i := 0; i < 50; i++ {
+			net.TrainBatch(xorInputs, xorTargets, MSE{}, opt)
+		}
+		return net.Predict([]float64{1, 0})
+	}
+
+	if a, b := train(), train(); a[0] != b[0] {
+		t.Errorf("training with the same seed gave %v and %v", a[0], b[0])
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	net := NewNetwork([]int{2, 3, 1}, Tanh, Linear, rand.New(rand.NewSource(3)))
+	filename := filepath.Join(t.TempDir(), "net.json")
+
+	if err := net.Save(filename); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := Load(filename)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	for _, input := range xorInputs {
+		if got, want := loaded.Predict(input)[0], net.Predict(input)[0]; got != want {
+			t.Errorf("loaded Predict(%v) = %v, want %v", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package nn
+
+import "math"
+
+// Optimizer applies the gradients accumulated in a network and clears them
+type Optimizer interface {
+	Step(net *Network, batchSize int)
+}
+
+// SGD is plain stochastic gradient descent
+type SGD struct {
+	LearningRate float64
+}
+
+func (s *SGD) Step(net *Network, batchSize int) {
+	scale := s.LearningRate / float64(batchSize)
+	for _, layer := range net.Layers {
+		if layer.weightGrads == nil {
+			continue
+		}
+		for o := range layer.Weights {
+			for i := range layer.Weights[o] {
+				layer.Weights[o][i] -= scale * layer.weightGrads[o][i]
+			}
+			layer.Biases[o] -= scale * layer.biasGrads[o]
+		}
+		layer.zeroGrads()
+	}
+}
+
+// Adam keeps running estimates of each parameter's first and second moments
+type Adam struct {
+	LearningRate float64
+	Beta1        float64
+	Beta2        float64
+	Epsilon      float64
+
+	step int
+	m    [][][]float64
+	v    [][][]float64
+	mb   [][]float64
+	vb   [][]float64
+}
+
+func NewAdam(learningRate float64) *Adam {
+	return &Adam{
+		LearningRate: learningRate,
+		Beta1:        0.9,
+		Beta2:        0.999,
+		Epsilon:      1e-8,
+	}
+}
+
+func (a *Adam) Step(net *Network, batchSize int) {
+	if a.m == nil {
+		a.init(net)
+	}
+	a.step++
+	correction1 := 1 - math.Pow(a.Beta1, float64(a.step))
+	correction2 := 1 - math.Pow(a.Beta2, float64(a.step))
+
+	update := func(param, m, v *float64, grad float64) {
+		*m = a.Beta1**m + (1-a.Beta1)*grad
+		*v = a.Beta2**v + (1-a.Beta2)*grad*grad
+		mHat := *m / correction1
+		vHat := *v / correction2
+		*param -= a.LearningRate * mHat / (math.Sqrt(vHat) + a.Epsilon)
+	}
+
+	for l, layer := range net.Layers {
+		if layer.weightGrads == nil {
+			continue
+		}
+		for o := range layer.Weights {
+			for i := range layer.Weights[o] {
+				grad := layer.weightGrads[o][i] / float64(batchSize)
+				update(&layer.Weights[o][i], &a.m[l][o][i], &a.v[l][o][i], grad)
+			}
+			grad := layer.biasGrads[o] / float64(batchSize)
+			update(&layer.Biases[o], &a.mb[l][o], &a.vb[l][o], grad)
+		}
+		layer.zeroGrads()
+	}
+}
+
+func (a *Adam) init(net *Network) {
+	a.m = make([][][]float64, len(net.Layers))
+	a.v = make([][][]float64, len(net.Layers))
+	a.mb = make([][]float64, len(net.Layers))
+	a.vb = make([][]float64, len(net.Layers))
+	for l, layer := range net.Layers {
+		a.m[l] = make([][]float64, len(layer.Weights))
+		a.v[l] = make([][]float64, len(layer.Weights))
+		for o := range layer.Weights {
+			a.m[l][o] = make([]float64, len(layer.Weights[o]))
+			a.v[l][o] = make([]float64, len(layer.Weights[o]))
+		}
+		a.mb[l] = make([]float64, len(layer.Biases))
+		a.vb[l] = make([]float64, len(layer.Biases))
+	}
+}
@@ -0,0 +1,302 @@
+// Package plot renders line and stacked-area charts as SVG or PNG using only
+// the standard library, for learning curves written by the metrics package.
+package plot
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Series is one named line on a chart. X and Y must have the same length.
+type Series struct {
+	Name string
+	X, Y []float64
+}
+
+// Chart is a set of series drawn on shared axes. When Stacked is set each
+// series is drawn as an area on top of the previous ones, so the series must
+// share their X values.
+type Chart struct {
+	Title   string
+	XLabel  string
+	YLabel  string
+	Series  []Series
+	Stacked bool
+
+	// YMin and YMax fix the Y range when YMax > YMin; otherwise it is fitted
+	// to the data
+	YMin, YMax float64
+}
+
+// Default image size in pixels
+const (
+	Width  = 800
+	Height = 500
+)
+
+// palette holds the series colors, reused in order
+var palette = []color.RGBA{
+	{31, 119, 180, 255},
+	{255, 127, 14, 255},
+	{44, 160, 44, 255},
+	{214, 39, 40, 255},
+	{148, 103, 189, 255},
+	{140, 86, 75, 255},
+	{227, 119, 194, 255},
+	{127, 127, 127, 255},
+}
+
+var (
+	black = color.RGBA{0, 0, 0, 255}
+	grey  = color.RGBA{220, 220, 220, 255}
+	white = color.RGBA{255, 255, 255, 255}
+)
+
+// SeriesColor returns the color used for the i-th series. Beyond the palette,
+// hues are spread by the golden angle so large overlays stay distinguishable.
+func SeriesColor(i int) color.RGBA {
+	if i < len(palette) {
+		return palette[i]
+	}
+	hue := math.Mod(float64(i-len(palette))*137.508, 360)
+	return hsv(hue, 0.65, 0.8)
+}
+
+// hsv converts a hue in degrees, saturation and value to RGB
+func hsv(h, s, v float64) color.RGBA {
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g = c, x
+	case h < 120:
+		r, g = x, c
+	case h < 180:
+		g, b = c, x
+	case h < 240:
+		g, b = x, c
+	case h < 300:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	m := v - c
+	return color.RGBA{uint8((r + m) * 255), uint8((g + m) * 255), uint8((b + m) * 255), 255}
+}
+
+type point struct{ x, y float64 }
+
+// canvas is the drawing surface shared by the SVG and PNG back ends
+type canvas interface {
+	line(a, b point, c color.RGBA, width float64)
+	polyline(points []point, c color.RGBA, width float64)
+	polygon(points []point, c color.RGBA)
+	rect(min, max point, fill color.RGBA, stroke color.RGBA)
+	// text draws s with its left end at p (or centered on p when center is
+	// set), p.y being the baseline
+	text(p point, s string, c color.RGBA, center bool)
+}
+
+// Margins around the plot area, in pixels. The right margin also holds the
+// legend and grows with the longest series name.
+const (
+	marginLeft   = 70
+	marginRight  = 170
+	marginTop    = 50
+	marginBottom = 60
+)
+
+// legendWidth returns the width of the legend box
+func (c *Chart) legendWidth() float64 {
+	width := 0
+	for _, s := range c.Series {
+		if w := textWidth(s.Name); w > width {
+			width = w
+		}
+	}
+	return float64(width + 40)
+}
+
+// stacked returns the cumulative Y values of each series
+func (c *Chart) stacked() [][]float64 {
+	tops := make([][]float64, len(c.Series))
+	for i, s := range c.Series {
+		tops[i] = make([]float64, len(s.Y))
+		for j, y := range s.Y {
+			tops[i][j] = y
+			if i > 0 && j < len(tops[i-1]) {
+				tops[i][j] += tops[i-1][j]
+			}
+		}
+	}
+	return tops
+}
+
+// bounds returns the data range covered by the chart
+func (c *Chart) bounds() (xMin, xMax, yMin, yMax float64) {
+	xMin, yMin = math.Inf(1), math.Inf(1)
+	xMax, yMax = math.Inf(-1), math.Inf(-1)
+	ys := make([][]float64, len(c.Series))
+	for i, s := range c.Series {
+		ys[i] = s.Y
+	}
+	if c.Stacked {
+		ys = c.stacked()
+		yMin = 0
+	}
+	for i, s := range c.Series {
+		for j, x := range s.X {
+			xMin = math.Min(xMin, x)
+			xMax = math.Max(xMax, x)
+			yMin = math.Min(yMin, ys[i][j])
+			yMax = math.Max(yMax, ys[i][j])
+		}
+	}
+	if math.IsInf(xMin, 1) {
+		xMin, xMax, yMin, yMax = 0, 1, 0, 1
+	}
+	if c.YMax > c.YMin {
+		yMin, yMax = c.YMin, c.YMax
+	}
+	if xMax == xMin {
+		xMax = xMin + 1
+	}
+	if yMax == yMin {
+		yMax = yMin + 1
+	}
+	return xMin, xMax, yMin, yMax
+}
+
+// ticks returns evenly spaced round values covering [min, max]
+func ticks(min, max float64, target int) []float64 {
+	raw := (max - min) / float64(target)
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := magnitude
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*magnitude >= raw {
+			step = m * magnitude
+			break
+		}
+	}
+	var values []float64
+	for v := math.Ceil(min/step-1e-9) * step; v <= max+step*1e-9; v += step {
+		values = append(values, v)
+	}
+	return values
+}
+
+// formatTick prints a tick value without trailing zeros, using k and M for
+// large numbers
+func formatTick(v float64) string {
+	// Remove the rounding noise of repeatedly adding the tick step
+	v = math.Round(v*1e9) / 1e9
+	switch {
+	case math.Abs(v) >= 1e6:
+		return strconv.FormatFloat(v/1e6, 'f', -1, 64) + "M"
+	case math.Abs(v) >= 1e4:
+		return strconv.FormatFloat(v/1e3, 'f', -1, 64) + "k"
+	case math.Abs(v) < 1e-9:
+		return "0"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// draw lays out the axes, series and legend on cv
+func (c *Chart) draw(cv canvas, width, height int) {
+	xMin, xMax, yMin, yMax := c.bounds()
+	left, top := float64(marginLeft), float64(marginTop)
+	right, bottom := float64(width)-math.Max(marginRight, c.legendWidth()+25), float64(height-marginBottom)
+	toPixel := func(x, y float64) point {
+		return point{
+			left + (x-xMin)/(xMax-xMin)*(right-left),
+			bottom - (y-yMin)/(yMax-yMin)*(bottom-top),
+		}
+	}
+
+	cv.rect(point{0, 0}, point{float64(width), float64(height)}, white, white)
+	cv.text(point{float64(width) / 2, 28}, c.Title, black, true)
+
+	// Grid lines and tick labels
+	for _, y := range ticks(yMin, yMax, 5) {
+		p := toPixel(xMin, y)
+		cv.line(point{left, p.y}, point{right, p.y}, grey, 1)
+		cv.text(point{left - 8 - float64(textWidth(formatTick(y))), p.y + 4}, formatTick(y), black, false)
+	}
+	for _, x := range ticks(xMin, xMax, 6) {
+		p := toPixel(x, yMin)
+		cv.line(point{p.x, top}, point{p.x, bottom}, grey, 1)
+		cv.text(point{p.x, bottom + 18}, formatTick(x), black, true)
+	}
+	cv.line(point{left, bottom}, point{right, bottom}, black, 1)
+	cv.line(point{left, top}, point{left, bottom}, black, 1)
+	cv.text(point{(left + right) / 2, float64(height) - 18}, c.XLabel, black, true)
+	cv.text(point{left - 50, top - 12}, c.YLabel, black, false)
+
+	// Series: stacked areas are filled between the previous top and this one
+	tops := c.stacked()
+	for i, s := range c.Series {
+		col := SeriesColor(i)
+		if c.Stacked {
+			var area []point
+			for j, x := range s.X {
+				area = append(area, toPixel(x, tops[i][j]))
+			}
+			for j := len(s.X) - 1; j >= 0; j-- {
+				base := 0.0
+				if i > 0 {
+					base = tops[i-1][j]
+				}
+				area = append(area, toPixel(s.X[j], math.Max(base, yMin)))
+			}
+			cv.polygon(area, col)
+			continue
+		}
+		var line []point
+		for j, x := range s.X {
+			line = append(line, toPixel(x, s.Y[j]))
+		}
+		cv.polyline(line, col, 2)
+	}
+
+	// Legend
+	if len(c.Series) > 0 {
+		legendLeft := right + 15
+		cv.rect(point{legendLeft, top}, point{legendLeft + c.legendWidth(), top + 10 + 20*float64(len(c.Series))}, white, black)
+		for i, s := range c.Series {
+			y := top + 20 + 20*float64(i)
+			cv.rect(point{legendLeft + 8, y - 8}, point{legendLeft + 22, y + 2}, SeriesColor(i), SeriesColor(i))
+			cv.text(point{legendLeft + 30, y + 1}, s.Name, black, false)
+		}
+	}
+}
+
+// WriteSVG renders the chart as an SVG document
+func (c *Chart) WriteSVG(w io.Writer) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	cv := newSVGCanvas(Width, Height)
+	c.draw(cv, Width, Height)
+	return cv.write(w)
+}
+
+// validate checks that every series has matching X and Y lengths
+func (c *Chart) validate() error {
+	for _, s := range c.Series {
+		if len(s.X) != len(s.Y) {
+			return fmt.Errorf("series %q has %d X values and %d Y values", s.Name, len(s.X), len(s.Y))
+		}
+	}
+	if c.Stacked && len(c.Series) > 0 {
+		for _, s := range c.Series[1:] {
+			if len(s.X) != len(c.Series[0].X) {
+				return fmt.Errorf("stacked series %q has %d points, want %d", s.Name, len(s.X), len(c.Series[0].X))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package plot
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jpotts18/tictactoe/metrics"
+)
+
+func testChart() *Chart {
+	return &Chart{
+		Title:  "Win rate <test>",
+		XLabel: "iteration",
+		YLabel: "win rate",
+		Series: []Series{
+			{Name: "qlearning", X: []float64{0, 5000, 10000}, Y: []float64{0.2, 0.5, 0.7}},
+			{Name: "sarsa", X: []float64{0, 5000, 10000}, Y: []float64{0.1, 0.4, 0.8}},
+		},
+	}
+}
+
+func TestTicks(t *testing.T) {
+	tests := []struct {
+		min, max float64
+		want     []float64
+	}{
+		{0, 1, []float64{0, 0.2, 0.4, 0.6, 0.8, 1}},
+		{0, 100000, []float64{0, 20000, 40000, 60000, 80000, 100000}},
+		{3, 17, []float64{5, 10, 15}},
+	}
+	for _, tt := range tests {
+		got := ticks(tt.min, tt.max, 5)
+		if len(got) != len(tt.want) {
+			t.Errorf("ticks(%v, %v) = %v, want %v", tt.min, tt.max, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if diff := got[i] - tt.want[i]; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("ticks(%v, %v) = %v, want %v", tt.min, tt.max, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testChart().WriteSVG(&buf); err != nil {
+		t.Fatalf("WriteSVG() error = %v", err)
+	}
+
+	// The document must be well-formed XML
+	decoder := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("invalid SVG: %v", err)
+		}
+	}
+	for _, want := range []string{"qlearning", "sarsa", "Win rate &lt;test&gt;", "<polyline", "10k"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("SVG does not contain %q", want)
+		}
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testChart().WritePNG(&buf); err != nil {
+		t.Fatalf("WritePNG() error = %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != Width || size.Y != Height {
+		t.Fatalf("image size = %v, want %dx%d", size, Width, Height)
+	}
+
+	// Both series colors must appear somewhere in the image
+	for i := range testChart().Series {
+		want := SeriesColor(i)
+		found := false
+		for y := 0; y < Height && !found; y++ {
+			for x := 0; x < Width && !found; x++ {
+				r, g, b, _ := img.At(x, y).RGBA()
+				found = uint8(r>>8) == want.R && uint8(g>>8) == want.G && uint8(b>>8) == want.B
+			}
+		}
+		if !found {
+			t.Errorf("series %d color not drawn", i)
+		}
+	}
+}
+
+func TestFontGlyphs(t *testing.T) {
+	for ch, glyph := range font {
+		rows := strings.Split(glyph, " ")
+		if len(rows) != glyphHeight {
+			t.Errorf("glyph %q has %d rows, want %d", ch, len(rows), glyphHeight)
+		}
+		for _, row := range rows {
+			if len(row) != glyphWidth {
+				t.Errorf("glyph %q has a row of %d pixels, want %d", ch, len(row), glyphWidth)
+			}
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	chart := testChart()
+	chart.Series[1].Y = chart.Series[1].Y[:2]
+	if err := chart.WriteSVG(io.Discard); err == nil {
+		t.Errorf("WriteSVG() accepted a series with mismatched lengths")
+	}
+}
+
+func TestWriteCharts(t *testing.T) {
+	dir := t.TempDir()
+	runs := []Run{
+		{Name: "qlearning", Records: []metrics.Record{
+			{Iteration: 5000, TableSize: 100, Greedy: true, AccuracyX: 0.5, AccuracyO: 0.4,
+				Results: []metrics.Result{{Opponent: "random", Wins: 60, Draws: 10, Losses: 30}}},
+			{Iteration: 10000, TableSize: 200, Greedy: true, AccuracyX: 0.7, AccuracyO: 0.6,
+				Results: []metrics.Result{{Opponent: "random", Wins: 70, Draws: 10, Losses: 20}}},
+		}},
+		{Name: "menace", Records: []metrics.Record{
+			{Iteration: 5000, Results: []metrics.Result{{Opponent: "random", Wins: 50, Draws: 20, Losses: 30}}},
+		}},
+	}
+
+	written, err := WriteCharts(dir, runs)
+	if err != nil {
+		t.Fatalf("WriteCharts() error = %v", err)
+	}
+	var names []string
+	for _, path := range written {
+		names = append(names, filepath.Base(path))
+		for _, ext := range []string{".svg", ".png"} {
+			if _, err := os.Stat(path + ext); err != nil {
+				t.Errorf("missing %s%s", path, ext)
+			}
+		}
+	}
+	want := []string{"accuracy", "exploitability", "outcomes-menace-random", "outcomes-qlearning-random",
+		"tablesize", "tderror", "winrate-random"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("WriteCharts() wrote %v, want %v", names, want)
+	}
+
+	// Only the greedy run has accuracy, but both overlay on the win-rate chart
+	if got := LearningCurves("", "", runs, Accuracy); len(got.Series) != 1 {
+		t.Errorf("accuracy chart has %d series, want 1", len(got.Series))
+	}
+	if got := LearningCurves("", "", runs, WinRate("random")); len(got.Series) != 2 {
+		t.Errorf("win-rate chart has %d series, want 2", len(got.Series))
+	}
+}
+
+func TestSeriesColorDistinct(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		c := SeriesColor(i)
+		key := hex(c)
+		if seen[key] {
+			t.Errorf("SeriesColor(%d) = %s repeats an earlier color", i, key)
+		}
+		seen[key] = true
+	}
+}
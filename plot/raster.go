@@ -0,0 +1,200 @@
+package plot
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// rasterCanvas draws onto an RGBA image
+type rasterCanvas struct {
+	img *image.RGBA
+}
+
+func (r *rasterCanvas) set(x, y int, c color.RGBA) {
+	if image.Pt(x, y).In(r.img.Rect) {
+		r.img.SetRGBA(x, y, c)
+	}
+}
+
+// line steps along the longer axis, drawing a square pen of the given width
+// at each step
+func (r *rasterCanvas) line(a, b point, c color.RGBA, width float64) {
+	steps := int(math.Max(math.Abs(b.x-a.x), math.Abs(b.y-a.y))) + 1
+	pen := int(math.Max(1, math.Round(width)))
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x := int(math.Round(a.x + t*(b.x-a.x)))
+		y := int(math.Round(a.y + t*(b.y-a.y)))
+		for dx := 0; dx < pen; dx++ {
+			for dy := 0; dy < pen; dy++ {
+				r.set(x+dx-pen/2, y+dy-pen/2, c)
+			}
+		}
+	}
+}
+
+func (r *rasterCanvas) polyline(ps []point, c color.RGBA, width float64) {
+	for i := 1; i < len(ps); i++ {
+		r.line(ps[i-1], ps[i], c, width)
+	}
+}
+
+// polygon fills with the even-odd rule, one scanline per pixel row
+func (r *rasterCanvas) polygon(ps []point, c color.RGBA) {
+	if len(ps) < 3 {
+		return
+	}
+	minY, maxY := ps[0].y, ps[0].y
+	for _, p := range ps {
+		minY = math.Min(minY, p.y)
+		maxY = math.Max(maxY, p.y)
+	}
+	for y := int(math.Floor(minY)); y <= int(math.Ceil(maxY)); y++ {
+		scan := float64(y) + 0.5
+		var xs []float64
+		for i := range ps {
+			a, b := ps[i], ps[(i+1)%len(ps)]
+			if (a.y <= scan) != (b.y <= scan) {
+				xs = append(xs, a.x+(scan-a.y)/(b.y-a.y)*(b.x-a.x))
+			}
+		}
+		sort.Float64s(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			for x := int(math.Round(xs[i])); x < int(math.Round(xs[i+1])); x++ {
+				r.set(x, y, c)
+			}
+		}
+	}
+}
+
+func (r *rasterCanvas) rect(min, max point, fill color.RGBA, stroke color.RGBA) {
+	for y := int(min.y); y < int(max.y); y++ {
+		for x := int(min.x); x < int(max.x); x++ {
+			r.set(x, y, fill)
+		}
+	}
+	corners := []point{min, {max.x - 1, min.y}, {max.x - 1, max.y - 1}, {min.x, max.y - 1}, min}
+	r.polyline(corners, stroke, 1)
+}
+
+// Glyphs are 3x5 pixels drawn at fontScale, with one blank column between
+// characters
+const (
+	fontScale   = 2
+	glyphWidth  = 3
+	glyphHeight = 5
+)
+
+// textWidth returns the width in pixels of s in the chart font
+func textWidth(s string) int {
+	return len(s) * (glyphWidth + 1) * fontScale
+}
+
+func (r *rasterCanvas) text(p point, s string, c color.RGBA, center bool) {
+	x := int(p.x)
+	if center {
+		x -= textWidth(s) / 2
+	}
+	top := int(p.y) - glyphHeight*fontScale
+	for _, ch := range strings.ToUpper(s) {
+		glyph, ok := font[ch]
+		if !ok {
+			glyph = font['?']
+		}
+		for row := 0; row < glyphHeight; row++ {
+			for col := 0; col < glyphWidth; col++ {
+				if glyph[row*(glyphWidth+1)+col] != '#' {
+					continue
+				}
+				for dy := 0; dy < fontScale; dy++ {
+					for dx := 0; dx < fontScale; dx++ {
+						r.set(x+col*fontScale+dx, top+row*fontScale+dy, c)
+					}
+				}
+			}
+		}
+		x += (glyphWidth + 1) * fontScale
+	}
+}
+
+// Image renders the chart onto a new image of the given size
+func (c *Chart) Image(width, height int) (*image.RGBA, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	cv := &rasterCanvas{img: image.NewRGBA(image.Rect(0, 0, width, height))}
+	c.draw(cv, width, height)
+	return cv.img, nil
+}
+
+// WritePNG renders the chart as a PNG image
+func (c *Chart) WritePNG(w io.Writer) error {
+	img, err := c.Image(Width, Height)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// font maps each supported character to its 3x5 glyph, rows separated by
+// spaces. Lower case is drawn as upper case and anything else as '?'.
+var font = map[rune]string{
+	' ':  "... ... ... ... ...",
+	'0':  "### #.# #.# #.# ###",
+	'1':  ".#. ##. .#. .#. ###",
+	'2':  "### ..# ### #.. ###",
+	'3':  "### ..# ### ..# ###",
+	'4':  "#.# #.# ### ..# ..#",
+	'5':  "### #.. ### ..# ###",
+	'6':  "### #.. ### #.# ###",
+	'7':  "### ..# ..# ..# ..#",
+	'8':  "### #.# ### #.# ###",
+	'9':  "### #.# ### ..# ###",
+	'A':  ".#. #.# ### #.# #.#",
+	'B':  "##. #.# ##. #.# ##.",
+	'C':  ".## #.. #.. #.. .##",
+	'D':  "##. #.# #.# #.# ##.",
+	'E':  "### #.. ### #.. ###",
+	'F':  "### #.. ### #.. #..",
+	'G':  ".## #.. #.# #.# .##",
+	'H':  "#.# #.# ### #.# #.#",
+	'I':  "### .#. .#. .#. ###",
+	'J':  "..# ..# ..# #.# .#.",
+	'K':  "#.# #.# ##. #.# #.#",
+	'L':  "#.. #.. #.. #.. ###",
+	'M':  "#.# ### ### #.# #.#",
+	'N':  "##. #.# #.# #.# #.#",
+	'O':  ".#. #.# #.# #.# .#.",
+	'P':  "##. #.# ##. #.. #..",
+	'Q':  ".#. #.# #.# ##. .##",
+	'R':  "##. #.# ##. #.# #.#",
+	'S':  ".## #.. .#. ..# ##.",
+	'T':  "### .#. .#. .#. .#.",
+	'U':  "#.# #.# #.# #.# ###",
+	'V':  "#.# #.# #.# #.# .#.",
+	'W':  "#.# #.# ### ### #.#",
+	'X':  "#.# #.# .#. #.# #.#",
+	'Y':  "#.# #.# .#. .#. .#.",
+	'Z':  "### ..# .#. #.. ###",
+	'.':  "... ... ... ... .#.",
+	',':  "... ... ... .#. #..",
+	':':  "... .#. ... .#. ...",
+	'-':  "... ... ### ... ...",
+	'+':  "... .#. ### .#. ...",
+	'_':  "... ... ... ... ###",
+	'/':  "..# ..# .#. #.. #..",
+	'(':  "..# .#. .#. .#. ..#",
+	')':  "#.. .#. .#. .#. #..",
+	'<':  "..# .#. #.. .#. ..#",
+	'>':  "#.. .#. ..# .#. #..",
+	'%':  "#.# ..# .#. #.. #.#",
+	'=':  "... ### ... ### ...",
+	'?':  "### ..# .#. ... .#.",
+	'*':  "... #.# .#. #.# ...",
+	'\'': ".#. .#. ... ... ...",
+}
@@ -0,0 +1,194 @@
+package plot
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jpotts18/tictactoe/metrics"
+)
+
+// Run is the learning curve of one training run
+type Run struct {
+	Name    string
+	Records []metrics.Record
+}
+
+// LoadRun reads a metrics JSON-lines file, naming the run after the file
+func LoadRun(filename string) (Run, error) {
+	records, err := metrics.ReadJSONL(filename)
+	if err != nil {
+		return Run{}, err
+	}
+	return Run{Name: strings.TrimSuffix(filepath.Base(filename), ".jsonl"), Records: records}, nil
+}
+
+// Metric extracts one value from a record, reporting false when the record
+// does not have it
+type Metric func(r metrics.Record) (float64, bool)
+
+// Accuracy is the mean of the X and O tablebase accuracies
+func Accuracy(r metrics.Record) (float64, bool) {
+	return (r.AccuracyX + r.AccuracyO) / 2, r.Greedy
+}
+
+// Exploitability is the mean of the X and O exploitabilities
+func Exploitability(r metrics.Record) (float64, bool) {
+	return (r.ExploitabilityX + r.ExploitabilityO) / 2, r.Greedy
+}
+
+// TableSize is the number of states in the agent's table
+func TableSize(r metrics.Record) (float64, bool) {
+	return float64(r.TableSize), true
+}
+
+// TDError is the mean absolute TD error of the window
+func TDError(r metrics.Record) (float64, bool) {
+	return r.MeanAbsTDError, true
+}
+
+// WinRate is the fraction of games won against opponent
+func WinRate(opponent string) Metric {
+	return func(r metrics.Record) (float64, bool) {
+		for _, result := range r.Results {
+			if result.Opponent == opponent && result.Games() > 0 {
+				return float64(result.Wins) / float64(result.Games()), true
+			}
+		}
+		return 0, false
+	}
+}
+
+// LearningCurves overlays metric against iteration for every run, one series
+// per run. Runs without the metric are left out.
+func LearningCurves(title, yLabel string, runs []Run, metric Metric) *Chart {
+	chart := &Chart{Title: title, XLabel: "iteration", YLabel: yLabel}
+	for _, run := range runs {
+		s := Series{Name: run.Name}
+		for _, r := range run.Records {
+			if y, ok := metric(r); ok {
+				s.X = append(s.X, float64(r.Iteration))
+				s.Y = append(s.Y, y)
+			}
+		}
+		if len(s.X) > 0 {
+			chart.Series = append(chart.Series, s)
+		}
+	}
+	return chart
+}
+
+// Outcomes stacks the win, draw and loss rates of a run against opponent
+func Outcomes(run Run, opponent string) *Chart {
+	chart := &Chart{
+		Title:   run.Name + " vs " + opponent,
+		XLabel:  "iteration",
+		YLabel:  "fraction of games",
+		Stacked: true,
+		YMin:    0,
+		YMax:    1,
+	}
+	wins, draws, losses := Series{Name: "win"}, Series{Name: "draw"}, Series{Name: "loss"}
+	for _, r := range run.Records {
+		for _, result := range r.Results {
+			if result.Opponent != opponent || result.Games() == 0 {
+				continue
+			}
+			x, games := float64(r.Iteration), float64(result.Games())
+			wins.X, wins.Y = append(wins.X, x), append(wins.Y, float64(result.Wins)/games)
+			draws.X, draws.Y = append(draws.X, x), append(draws.Y, float64(result.Draws)/games)
+			losses.X, losses.Y = append(losses.X, x), append(losses.Y, float64(result.Losses)/games)
+		}
+	}
+	if len(wins.X) > 0 {
+		chart.Series = []Series{wins, draws, losses}
+	}
+	return chart
+}
+
+// opponents returns the benchmark names found in the runs, in first-seen order
+func opponents(runs []Run) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, run := range runs {
+		for _, r := range run.Records {
+			for _, result := range r.Results {
+				if !seen[result.Opponent] {
+					seen[result.Opponent] = true
+					names = append(names, result.Opponent)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// WriteFiles renders chart to <path>.svg and <path>.png
+func WriteFiles(chart *Chart, path string) error {
+	svgFile, err := os.Create(path + ".svg")
+	if err != nil {
+		return err
+	}
+	if err := chart.WriteSVG(svgFile); err != nil {
+		svgFile.Close()
+		return err
+	}
+	if err := svgFile.Close(); err != nil {
+		return err
+	}
+
+	pngFile, err := os.Create(path + ".png")
+	if err != nil {
+		return err
+	}
+	if err := chart.WritePNG(pngFile); err != nil {
+		pngFile.Close()
+		return err
+	}
+	return pngFile.Close()
+}
+
+// fileName makes run names safe to use in file names
+var fileName = strings.NewReplacer("/", "_", " ", "_", string(filepath.Separator), "_")
+
+// WriteCharts renders the standard charts for a set of runs into dir: overlaid
+// accuracy, exploitability, win-rate and table-size curves, and a stacked
+// win/draw/loss chart per run and benchmark. It returns the paths written,
+// without extensions.
+func WriteCharts(dir string, runs []Run) ([]string, error) {
+	charts := map[string]*Chart{
+		"accuracy":       LearningCurves("Optimal move accuracy", "accuracy", runs, Accuracy),
+		"exploitability": LearningCurves("Exploitability", "exploitability", runs, Exploitability),
+		"tablesize":      LearningCurves("Q-table growth", "states", runs, TableSize),
+		"tderror":        LearningCurves("Mean absolute TD error", "TD error", runs, TDError),
+	}
+	for _, opponent := range opponents(runs) {
+		chart := LearningCurves("Win rate vs "+opponent, "win rate", runs, WinRate(opponent))
+		chart.YMin, chart.YMax = 0, 1
+		charts["winrate-"+opponent] = chart
+		for _, run := range runs {
+			charts["outcomes-"+run.Name+"-"+opponent] = Outcomes(run, opponent)
+		}
+	}
+
+	names := make([]string, 0, len(charts))
+	for name := range charts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var written []string
+	for _, name := range names {
+		chart := charts[name]
+		if len(chart.Series) == 0 {
+			continue
+		}
+		path := filepath.Join(dir, fileName.Replace(name))
+		if err := WriteFiles(chart, path); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
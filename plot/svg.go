@@ -0,0 +1,72 @@
+package plot
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+)
+
+// svgCanvas collects SVG elements
+type svgCanvas struct {
+	width, height int
+	sb            strings.Builder
+}
+
+func newSVGCanvas(width, height int) *svgCanvas {
+	return &svgCanvas{width: width, height: height}
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func points(ps []point) string {
+	parts := make([]string, len(ps))
+	for i, p := range ps {
+		parts[i] = fmt.Sprintf("%.1f,%.1f", p.x, p.y)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (s *svgCanvas) line(a, b point, c color.RGBA, width float64) {
+	fmt.Fprintf(&s.sb, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"%s\" stroke-width=\"%g\"/>\n",
+		a.x, a.y, b.x, b.y, hex(c), width)
+}
+
+func (s *svgCanvas) polyline(ps []point, c color.RGBA, width float64) {
+	fmt.Fprintf(&s.sb, "<polyline points=\"%s\" fill=\"none\" stroke=\"%s\" stroke-width=\"%g\"/>\n",
+		points(ps), hex(c), width)
+}
+
+func (s *svgCanvas) polygon(ps []point, c color.RGBA) {
+	fmt.Fprintf(&s.sb, "<polygon points=\"%s\" fill=\"%s\"/>\n", points(ps), hex(c))
+}
+
+func (s *svgCanvas) rect(min, max point, fill color.RGBA, stroke color.RGBA) {
+	fmt.Fprintf(&s.sb, "<rect x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\" fill=\"%s\" stroke=\"%s\"/>\n",
+		min.x, min.y, max.x-min.x, max.y-min.y, hex(fill), hex(stroke))
+}
+
+func (s *svgCanvas) text(p point, str string, c color.RGBA, center bool) {
+	anchor := "start"
+	if center {
+		anchor = "middle"
+	}
+	var escaped strings.Builder
+	xmlEscape(&escaped, str)
+	fmt.Fprintf(&s.sb, "<text x=\"%.1f\" y=\"%.1f\" fill=\"%s\" text-anchor=\"%s\">%s</text>\n",
+		p.x, p.y, hex(c), anchor, escaped.String())
+}
+
+func xmlEscape(sb *strings.Builder, str string) {
+	strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\"", "&quot;").WriteString(sb, str)
+}
+
+// write emits the complete document. Text uses a monospace font so label
+// widths match the layout computed with textWidth.
+func (s *svgCanvas) write(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"monospace\" font-size=\"13\">\n%s</svg>\n",
+		s.width, s.height, s.width, s.height, s.sb.String())
+	return err
+}
@@ -0,0 +1,136 @@
+// Package reward defines the rewards learning agents are trained on. Every
+// reward is computed from the perspective of the player it is given to, so
+// the same function serves both seats without sign flips.
+package reward
+
+import (
+	"fmt"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+// Function returns the reward player receives for the transition from before
+// to after. before is the board player moved on; after is the next board
+// player sees, or the final board if the game ended.
+type Function interface {
+	Reward(before, after *game.Board, player int) float64
+}
+
+// Func adapts an ordinary function to Function
+type Func func(before, after *game.Board, player int) float64
+
+func (f Func) Reward(before, after *game.Board, player int) float64 {
+	return f(before, after, player)
+}
+
+// Outcome returns 1 if player has won on board, -1 if player has lost, 0 for
+// a draw, and ok false if the game is not over
+func Outcome(board *game.Board, player int) (outcome int, ok bool) {
+	over, winner := board.IsGameOver()
+	switch {
+	case !over:
+		return 0, false
+	case winner == 0:
+		return 0, true
+	case winner == player:
+		return 1, true
+	default:
+		return -1, true
+	}
+}
+
+// Scheme pays Win, Draw or Loss when the game ends and Step for every move
+// before that
+type Scheme struct {
+	Win  float64 `json:"win"`
+	Draw float64 `json:"draw"`
+	Loss float64 `json:"loss"`
+	Step float64 `json:"step"`
+}
+
+func (s Scheme) Reward(before, after *game.Board, player int) float64 {
+	outcome, over := Outcome(after, player)
+	switch {
+	case !over:
+		return s.Step
+	case outcome > 0:
+		return s.Win
+	case outcome < 0:
+		return s.Loss
+	default:
+		return s.Draw
+	}
+}
+
+func (s Scheme) String() string {
+	return fmt.Sprintf("win %g, draw %g, loss %g, step %g", s.Win, s.Draw, s.Loss, s.Step)
+}
+
+// Sparse rewards only the result: 1 for a win, 0 for a draw and -1 for a loss
+func Sparse() Scheme {
+	return Scheme{Win: 1, Draw: 0, Loss: -1}
+}
+
+// Potential scores how promising board is for player
+type Potential func(board *game.Board, player int) float64
+
+// Shaped adds the potential-based shaping term Gamma*Φ(after) - Φ(before) to
+// Base. Shaping of this form speeds up learning without changing which
+// policies are optimal. Φ is taken as 0 on finished boards, so the shaping
+// terms of an episode sum to -Φ(start) whatever happens.
+type Shaped struct {
+	Base      Function
+	Potential Potential
+	Gamma     float64
+}
+
+func (s Shaped) Reward(before, after *game.Board, player int) float64 {
+	return s.Base.Reward(before, after, player) +
+		s.Gamma*s.potential(after, player) - s.potential(before, player)
+}
+
+func (s Shaped) potential(board *game.Board, player int) float64 {
+	if over, _ := board.IsGameOver(); over {
+		return 0
+	}
+	return s.Potential(board, player)
+}
+
+func (s Shaped) String() string {
+	return fmt.Sprintf("%v, shaped", s.Base)
+}
+
+// lines are the rows, columns and diagonals of the board
+var lines = [8][3]int{
+	{0, 1, 2}, {3, 4, 5}, {6, 7, 8},
+	{0, 3, 6}, {1, 4, 7}, {2, 5, 8},
+	{0, 4, 8}, {2, 4, 6},
+}
+
+// Threats returns a potential worth weight for each line where player has
+// two marks and the third cell is empty, minus weight for each such line of
+// the opponent. Creating a threat raises it and blocking one removes the
+// opponent's penalty.
+func Threats(weight float64) Potential {
+	return func(board *game.Board, player int) float64 {
+		count := 0
+		for _, line := range lines {
+			mine, theirs := 0, 0
+			for _, cell := range line {
+				switch board.GetCell(cell) {
+				case 0:
+				case player:
+					mine++
+				default:
+					theirs++
+				}
+			}
+			if mine == 2 && theirs == 0 {
+				count++
+			} else if theirs == 2 && mine == 0 {
+				count--
+			}
+		}
+		return weight * float64(count)
+	}
+}
@@ -0,0 +1,143 @@
+package reward
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+var scheme = Scheme{Win: 1, Draw: 0.5, Loss: -2, Step: -0.01}
+
+func TestSchemeSigns(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+		player        int
+		want          float64
+	}{
+		{"X completes a row", "110220000", "111220000", 1, scheme.Win},
+		{"O sees X complete a row", "110220000", "111220000", 2, scheme.Loss},
+		{"O completes a column", "210210001", "210210201", 2, scheme.Win},
+		{"X sees O complete a column", "210210001", "210210201", 1, scheme.Loss},
+		{"X fills the last cell for a draw", "121211200", "121211212", 1, scheme.Draw},
+		{"O sees the draw", "121211200", "121211212", 2, scheme.Draw},
+		{"X plays on", "000000000", "000010000", 1, scheme.Step},
+		{"O plays on", "000010000", "200010000", 2, scheme.Step},
+	}
+	for _, tt := range tests {
+		got := scheme.Reward(game.FromStateString(tt.before), game.FromStateString(tt.after), tt.player)
+		if got != tt.want {
+			t.Errorf("%s: Reward() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// swap exchanges the marks of the two players
+func swap(state string) *game.Board {
+	swapped := []byte(state)
+	for i, c := range swapped {
+		switch c {
+		case '1':
+			swapped[i] = '2'
+		case '2':
+			swapped[i] = '1'
+		}
+	}
+	return game.FromStateString(string(swapped))
+}
+
+func TestRewardsAreSeatSymmetric(t *testing.T) {
+	functions := map[string]Function{
+		"scheme": scheme,
+		"sparse": Sparse(),
+		"shaped": Shaped{Base: Sparse(), Potential: Threats(0.1), Gamma: 0.9},
+	}
+	transitions := [][2]string{
+		{"110220000", "111220000"},
+		{"100020000", "100020001"},
+		{"120000000", "120010000"},
+		{"121211200", "121211212"},
+	}
+	for name, f := range functions {
+		for _, tr := range transitions {
+			for _, player := range []int{1, 2} {
+				a := f.Reward(game.FromStateString(tr[0]), game.FromStateString(tr[1]), player)
+				b := f.Reward(swap(tr[0]), swap(tr[1]), 3-player)
+				if math.Abs(a-b) > 1e-12 {
+					t.Errorf("%s: %v for player %d is %v, but %v with the marks swapped", name, tr, player, a, b)
+				}
+			}
+		}
+	}
+}
+
+func TestThreats(t *testing.T) {
+	threats := Threats(1)
+	tests := []struct {
+		state  string
+		player int
+		want   float64
+	}{
+		{"000000000", 1, 0},
+		{"110000000", 1, 1},
+		{"110000000", 2, -1},
+		{"112000000", 1, 0}, // blocked
+		{"110100000", 1, 2}, // a fork: row and column
+		{"110220000", 2, 0}, // one threat each
+		{"101020020", 1, 0}, // X's row threat against O's column threat
+	}
+	for _, tt := range tests {
+		if got := threats(game.FromStateString(tt.state), tt.player); got != tt.want {
+			t.Errorf("Threats(%s, %d) = %v, want %v", tt.state, tt.player, got, tt.want)
+		}
+	}
+}
+
+func TestShaped(t *testing.T) {
+	shaped := Shaped{Base: Sparse(), Potential: Threats(0.1), Gamma: 1}
+	reward := func(before, after string, player int) float64 {
+		return shaped.Reward(game.FromStateString(before), game.FromStateString(after), player)
+	}
+
+	// Creating a threat is rewarded, blocking one is rewarded, and walking
+	// into a position with an opponent threat is penalized
+	if got := reward("100020000", "110020000", 1); got <= 0 {
+		t.Errorf("creating a threat: Reward() = %v, want > 0", got)
+	}
+	if got := reward("110020000", "112020000", 2); got <= 0 {
+		t.Errorf("blocking a threat: Reward() = %v, want > 0", got)
+	}
+	if got := reward("120000000", "120000010", 1); got != 0 {
+		t.Errorf("a quiet move: Reward() = %v, want 0", got)
+	}
+
+	// Along any game the shaping terms telescope to -Φ(start), so the return
+	// differs from the sparse one only by a constant
+	games := [][]string{
+		{"000000000", "100000000", "100020000", "110020000", "112020000", "112020100", "112220100", "112221100", "112221120", "112221121"},
+		{"000000000", "000010000", "020010000", "120010000", "122010000", "122010001"},
+	}
+	for _, states := range games {
+		for _, player := range []int{1, 2} {
+			shapedReturn, sparseReturn := 0.0, 0.0
+			for i := 1; i < len(states); i++ {
+				before, after := game.FromStateString(states[i-1]), game.FromStateString(states[i])
+				shapedReturn += shaped.Reward(before, after, player)
+				sparseReturn += Sparse().Reward(before, after, player)
+			}
+			if math.Abs(shapedReturn-sparseReturn) > 1e-12 {
+				t.Errorf("player %d: shaped return %v differs from sparse return %v", player, shapedReturn, sparseReturn)
+			}
+		}
+	}
+}
+
+func TestFunc(t *testing.T) {
+	moves := Func(func(before, after *game.Board, player int) float64 {
+		return float64(len(before.GetEmptyCells()) - len(after.GetEmptyCells()))
+	})
+	if got := moves.Reward(game.FromStateString("000000000"), game.FromStateString("100020000"), 1); got != 2 {
+		t.Errorf("Reward() = %v, want 2", got)
+	}
+}
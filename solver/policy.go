@@ -0,0 +1,100 @@
+package solver
+
+import (
+	"strconv"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+// Policy returns the move a deterministic player makes in a position
+type Policy func(board *game.Board, player int) int
+
+// boardAt rebuilds the board with the given base-3 index
+func boardAt(index int) *game.Board {
+	state := ""
+	for i := 0; i < 9; i++ {
+		state += strconv.Itoa(index % 3)
+		index /= 3
+	}
+	return game.FromStateString(state)
+}
+
+// Accuracy returns the fraction of reachable, unfinished positions with
+// player to move in which policy's move keeps the game-theoretic outcome
+func (t *Tablebase) Accuracy(policy Policy, player int) float64 {
+	positions, optimal := 0, 0
+	offset := (player - 1) * numStates
+	for index := 0; index < numStates; index++ {
+		if t.entries[offset+index] == 0 {
+			continue
+		}
+		board := boardAt(index)
+		if over, _ := board.IsGameOver(); over {
+			continue
+		}
+		positions++
+		boardCopy := *board
+		if t.IsOptimal(board, player, policy(&boardCopy, player)) {
+			optimal++
+		}
+	}
+	if positions == 0 {
+		return 0
+	}
+	return float64(optimal) / float64(positions)
+}
+
+// BestResponse returns the outcome, from the adversary's point of view, that
+// a perfect adversary achieves against policy playing player when first moves
+// first. An illegal move by the policy counts as a loss for it.
+func BestResponse(policy Policy, player, first int) Outcome {
+	memo := make(map[int]Outcome)
+	var respond func(board *game.Board, toMove int) Outcome
+	respond = func(board *game.Board, toMove int) Outcome {
+		if over, winner := board.IsGameOver(); over {
+			switch winner {
+			case 0:
+				return Draw
+			case player:
+				return Loss
+			default:
+				return Win
+			}
+		}
+		k := (toMove-1)*numStates + Index(board)
+		if outcome, ok := memo[k]; ok {
+			return outcome
+		}
+
+		var outcome Outcome
+		if toMove == player {
+			boardCopy := *board
+			move := policy(&boardCopy, player)
+			if move < 0 || move > 8 || !boardCopy.MakeMove(move, player) {
+				outcome = Win
+			} else {
+				outcome = respond(&boardCopy, player%2+1)
+			}
+		} else {
+			outcome = Loss
+			for _, move := range board.GetAvailableMoves() {
+				boardCopy := *board
+				boardCopy.MakeMove(move, toMove)
+				if reply := respond(&boardCopy, player); reply > outcome {
+					outcome = reply
+				}
+			}
+		}
+		memo[k] = outcome
+		return outcome
+	}
+	return respond(game.NewBoard(), first)
+}
+
+// Exploitability averages a perfect adversary's best-response outcome against
+// policy over both choices of first player. It is 0 for a policy that never
+// loses and 1 for one that loses every game.
+func Exploitability(policy Policy, player int) float64 {
+	total := BestResponse(policy, player, 1) + BestResponse(policy, player, 2)
+	return float64(total) / 2
+}
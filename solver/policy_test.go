@@ -0,0 +1,55 @@
+package solver
+
+import (
+	"testing"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+func perfectPolicy(board *game.Board, player int) int {
+	return BestMoves(board, player)[0]
+}
+
+func firstEmptyPolicy(board *game.Board, player int) int {
+	return board.GetAvailableMoves()[0]
+}
+
+func TestAccuracy(t *testing.T) {
+	tb := Default()
+	for _, player := range []int{1, 2} {
+		if got := tb.Accuracy(perfectPolicy, player); got != 1 {
+			t.Errorf("Accuracy(perfect, %d) = %v, want 1", player, got)
+		}
+		if got := tb.Accuracy(firstEmptyPolicy, player); got <= 0 || got >= 1 {
+			t.Errorf("Accuracy(firstEmpty, %d) = %v, want strictly between 0 and 1", player, got)
+		}
+	}
+}
+
+func TestExploitability(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		want   float64
+	}{
+		{"perfect policy", perfectPolicy, 0},
+		{"first empty square", firstEmptyPolicy, 1},
+		{"illegal move", func(board *game.Board, player int) int { return -1 }, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, player := range []int{1, 2} {
+				if got := Exploitability(tt.policy, player); got != tt.want {
+					t.Errorf("Exploitability(%d) = %v, want %v", player, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBoardAt(t *testing.T) {
+	b := game.FromStateString("120000201")
+	if got := boardAt(Index(b)); *got != *b {
+		t.Errorf("boardAt(Index()) = %v, want %v", got, b)
+	}
+}
@@ -0,0 +1,153 @@
+// Package solver computes the game-theoretic value of tic-tac-toe positions.
+package solver
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+// Outcome is the result of a position under perfect play, from the point of
+// view of the player to move
+type Outcome int
+
+const (
+	Loss Outcome = -1
+	Draw Outcome = 0
+	Win  Outcome = 1
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Win:
+		return "win"
+	case Loss:
+		return "loss"
+	default:
+		return "draw"
+	}
+}
+
+// Value is a position's outcome and the number of plies until the game ends
+// when the winner wins as fast as possible and the loser holds out as long as
+// possible
+type Value struct {
+	Outcome  Outcome
+	Distance int
+}
+
+func (v Value) String() string {
+	if v.Outcome == Draw {
+		return "draw"
+	}
+	return fmt.Sprintf("%s in %d", v.Outcome, v.Distance)
+}
+
+// better reports whether a is preferable to b for the player it belongs to
+func better(a, b Value) bool {
+	if a.Outcome != b.Outcome {
+		return a.Outcome > b.Outcome
+	}
+	switch a.Outcome {
+	case Win:
+		return a.Distance < b.Distance
+	case Loss:
+		return a.Distance > b.Distance
+	default:
+		return false
+	}
+}
+
+// MoveValue is the value of playing a move, for the player who plays it
+type MoveValue struct {
+	Move  int
+	Value Value
+}
+
+// PlayerToMove infers whose turn it is from the mark counts. Player 1 is
+// assumed to move first unless player 2 already has more marks.
+func PlayerToMove(board *game.Board) int {
+	ones, twos := 0, 0
+	for i := 0; i < 9; i++ {
+		switch board.GetCell(i) {
+		case 1:
+			ones++
+		case 2:
+			twos++
+		}
+	}
+	if ones > twos {
+		return 2
+	}
+	return 1
+}
+
+var cache sync.Map
+
+func key(board *game.Board, player int) string {
+	k := strconv.Itoa(player)
+	for i := 0; i < 9; i++ {
+		k += strconv.Itoa(board.GetCell(i))
+	}
+	return k
+}
+
+// Solve returns the value of the position for player, who is to move
+func Solve(board *game.Board, player int) Value {
+	k := key(board, player)
+	if v, ok := cache.Load(k); ok {
+		return v.(Value)
+	}
+
+	var value Value
+	if over, winner := board.IsGameOver(); over {
+		switch winner {
+		case 0:
+			value = Value{Draw, 0}
+		case player:
+			value = Value{Win, 0}
+		default:
+			value = Value{Loss, 0}
+		}
+	} else {
+		moves := MoveValues(board, player)
+		value = moves[0].Value
+		for _, mv := range moves[1:] {
+			if better(mv.Value, value) {
+				value = mv.Value
+			}
+		}
+	}
+
+	cache.Store(k, value)
+	return value
+}
+
+// MoveValues returns the value of each legal move for the player making it
+func MoveValues(board *game.Board, player int) []MoveValue {
+	var values []MoveValue
+	for _, move := range board.GetAvailableMoves() {
+		boardCopy := *board
+		boardCopy.MakeMove(move, player)
+		reply := Solve(&boardCopy, player%2+1)
+		values = append(values, MoveValue{
+			Move:  move,
+			Value: Value{Outcome: -reply.Outcome, Distance: reply.Distance + 1},
+		})
+	}
+	return values
+}
+
+// BestMoves returns every move that achieves the position's value
+func BestMoves(board *game.Board, player int) []int {
+	target := Solve(board, player)
+	var best []int
+	for _, mv := range MoveValues(board, player) {
+		if mv.Value == target {
+			best = append(best, mv.Move)
+		}
+	}
+	return best
+}
@@ -0,0 +1,109 @@
+package solver
+
+import (
+	"testing"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name   string
+		state  [][]int
+		player int
+		want   Value
+	}{
+		{
+			name: "empty board is a draw",
+			state: [][]int{
+				{0, 0, 0},
+				{0, 0, 0},
+				{0, 0, 0},
+			},
+			player: 1,
+			want:   Value{Draw, 9},
+		},
+		{
+			name: "immediate win",
+			state: [][]int{
+				{1, 1, 0},
+				{2, 2, 0},
+				{0, 0, 0},
+			},
+			player: 1,
+			want:   Value{Win, 1},
+		},
+		{
+			name: "center reply to corner opening draws",
+			state: [][]int{
+				{1, 0, 0},
+				{0, 2, 0},
+				{0, 0, 0},
+			},
+			player: 1,
+			want:   Value{Draw, 7},
+		},
+		{
+			name: "edge reply to corner opening loses",
+			state: [][]int{
+				{1, 2, 0},
+				{0, 0, 0},
+				{0, 0, 0},
+			},
+			player: 1,
+			want:   Value{Win, 5},
+		},
+		{
+			name: "finished game",
+			state: [][]int{
+				{2, 2, 2},
+				{1, 1, 0},
+				{1, 0, 0},
+			},
+			player: 1,
+			want:   Value{Loss, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := game.NewBoard()
+			b.SetState(tt.state)
+			if got := Solve(b, tt.player); got != tt.want {
+				t.Errorf("Solve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBestMoves(t *testing.T) {
+	b := game.NewBoard()
+	b.SetState([][]int{
+		{1, 1, 0},
+		{0, 2, 0},
+		{0, 0, 0},
+	})
+
+	// Player 2 must block at 2; every other move loses at once
+	best := BestMoves(b, 2)
+	if len(best) != 1 || best[0] != 2 {
+		t.Errorf("BestMoves() = %v, want [2]", best)
+	}
+}
+
+func TestPlayerToMove(t *testing.T) {
+	tests := []struct {
+		state string
+		want  int
+	}{
+		{"000000000", 1},
+		{"100000000", 2},
+		{"120000000", 1},
+		{"200000000", 1},
+	}
+	for _, tt := range tests {
+		if got := PlayerToMove(game.FromStateString(tt.state)); got != tt.want {
+			t.Errorf("PlayerToMove(%v) = %v, want %v", tt.state, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+// numStates is the number of base-3 encodings of a 3x3 board
+const numStates = 19683
+
+// TablebaseSize is the size in bytes of a tablebase: one byte per board
+// encoding for each player to move
+const TablebaseSize = 2 * numStates
+
+// Tablebase stores the exact value of every position reachable from the empty
+// board with either player moving first. Each entry is one byte: bit 7 marks
+// the entry as present, bits 4-5 hold the outcome plus one and bits 0-3 the
+// distance to the end of the game.
+type Tablebase struct {
+	entries [TablebaseSize]byte
+}
+
+// Index returns the base-3 encoding of the board, with cell 0 as the least
+// significant digit
+func Index(board *game.Board) int {
+	index := 0
+	for i := 8; i >= 0; i-- {
+		index = index*3 + board.GetCell(i)
+	}
+	return index
+}
+
+func slot(board *game.Board, player int) int {
+	return (player-1)*numStates + Index(board)
+}
+
+func encode(v Value) byte {
+	return 0x80 | byte(v.Outcome+1)<<4 | byte(v.Distance)
+}
+
+func decode(b byte) Value {
+	return Value{Outcome: Outcome(b>>4&0x3) - 1, Distance: int(b & 0xf)}
+}
+
+// Build enumerates every reachable position by full search and records its
+// value
+func Build() *Tablebase {
+	t := &Tablebase{}
+	var visit func(board *game.Board, player int)
+	visit = func(board *game.Board, player int) {
+		s := slot(board, player)
+		if t.entries[s] != 0 {
+			return
+		}
+		t.entries[s] = encode(Solve(board, player))
+		if over, _ := board.IsGameOver(); over {
+			return
+		}
+		for _, move := range board.GetAvailableMoves() {
+			boardCopy := *board
+			boardCopy.MakeMove(move, player)
+			visit(&boardCopy, player%2+1)
+		}
+	}
+	visit(game.NewBoard(), 1)
+	visit(game.NewBoard(), 2)
+	return t
+}
+
+var (
+	defaultOnce sync.Once
+	defaultBase *Tablebase
+)
+
+// DefaultFile is where -tablebase saves the tablebase and Default looks for it
+const DefaultFile = "models/tictactoe.tb"
+
+// Default returns a shared tablebase, loaded from DefaultFile on first use, or
+// built if that file is missing or invalid
+func Default() *Tablebase {
+	defaultOnce.Do(func() {
+		defaultBase = loadOrBuild(DefaultFile)
+	})
+	return defaultBase
+}
+
+// loadOrBuild reads the tablebase in filename, or builds one if it cannot
+func loadOrBuild(filename string) *Tablebase {
+	if t, err := LoadTablebase(filename); err == nil {
+		return t
+	}
+	return Build()
+}
+
+// Len returns the number of positions in the tablebase
+func (t *Tablebase) Len() int {
+	n := 0
+	for _, b := range t.entries {
+		if b != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Lookup returns the value of the position for player, who is to move. It
+// reports false if the position cannot arise in a legal game.
+func (t *Tablebase) Lookup(board *game.Board, player int) (Value, bool) {
+	if player != 1 && player != 2 {
+		return Value{}, false
+	}
+	b := t.entries[slot(board, player)]
+	if b == 0 {
+		return Value{}, false
+	}
+	return decode(b), true
+}
+
+// MoveValues returns the value of each legal move for the player making it
+func (t *Tablebase) MoveValues(board *game.Board, player int) []MoveValue {
+	var values []MoveValue
+	for _, move := range board.GetAvailableMoves() {
+		boardCopy := *board
+		boardCopy.MakeMove(move, player)
+		reply, ok := t.Lookup(&boardCopy, player%2+1)
+		if !ok {
+			reply = Solve(&boardCopy, player%2+1)
+		}
+		values = append(values, MoveValue{
+			Move:  move,
+			Value: Value{Outcome: -reply.Outcome, Distance: reply.Distance + 1},
+		})
+	}
+	return values
+}
+
+// IsOptimal reports whether move preserves the position's game-theoretic
+// outcome for player
+func (t *Tablebase) IsOptimal(board *game.Board, player, move int) bool {
+	if move < 0 || move > 8 {
+		return false
+	}
+	value, ok := t.Lookup(board, player)
+	if !ok {
+		value = Solve(board, player)
+	}
+	for _, mv := range t.MoveValues(board, player) {
+		if mv.Move == move {
+			return mv.Value.Outcome == value.Outcome
+		}
+	}
+	return false
+}
+
+// Save writes the tablebase to filename
+func (t *Tablebase) Save(filename string) error {
+	return os.WriteFile(filename, t.entries[:], 0644)
+}
+
+// LoadTablebase reads a tablebase written by Save
+func LoadTablebase(filename string) (*Tablebase, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != TablebaseSize {
+		return nil, fmt.Errorf("tablebase %s has %d bytes, want %d", filename, len(data), TablebaseSize)
+	}
+	t := &Tablebase{}
+	copy(t.entries[:], data)
+	for _, b := range t.entries {
+		if b != 0 && (b&0x80 == 0 || b>>4&0x3 > 2 || b&0xf > 9) {
+			return nil, errors.New("tablebase contains an invalid entry")
+		}
+	}
+	return t, nil
+}
@@ -0,0 +1,117 @@
+package solver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jpotts18/tictactoe/game"
+)
+
+func TestTablebaseLen(t *testing.T) {
+	// 5478 legal positions with X moving first, and as many with O first
+	if got := Default().Len(); got != 2*5478 {
+		t.Errorf("Len() = %v, want %v", got, 2*5478)
+	}
+}
+
+func TestTablebaseMatchesSolve(t *testing.T) {
+	tb := Default()
+	var visit func(board *game.Board, player int)
+	visit = func(board *game.Board, player int) {
+		got, ok := tb.Lookup(board, player)
+		if !ok {
+			t.Fatalf("position %d with player %d to move is missing", Index(board), player)
+		}
+		if want := Solve(board, player); got != want {
+			t.Fatalf("Lookup(%d, %d) = %v, want %v", Index(board), player, got, want)
+		}
+		if over, _ := board.IsGameOver(); over {
+			return
+		}
+		for _, move := range board.GetAvailableMoves() {
+			boardCopy := *board
+			boardCopy.MakeMove(move, player)
+			visit(&boardCopy, player%2+1)
+		}
+	}
+	visit(game.NewBoard(), 1)
+}
+
+func TestTablebaseUnreachable(t *testing.T) {
+	// X cannot have three more marks than O
+	b := game.FromStateString("111000000")
+	if _, ok := Default().Lookup(b, 2); ok {
+		t.Errorf("Lookup() found an unreachable position")
+	}
+}
+
+func TestTablebaseIsOptimal(t *testing.T) {
+	b := game.FromStateString("110020000")
+	tests := []struct {
+		move int
+		want bool
+	}{
+		{2, true},
+		{3, false},
+		{8, false},
+	}
+	for _, tt := range tests {
+		if got := Default().IsOptimal(b, 2, tt.move); got != tt.want {
+			t.Errorf("IsOptimal(%d) = %v, want %v", tt.move, got, tt.want)
+		}
+	}
+}
+
+func TestTablebaseSaveLoad(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "tictactoe.tb")
+	if err := Default().Save(filename); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Size() != TablebaseSize {
+		t.Errorf("file size = %d, want %d", info.Size(), TablebaseSize)
+	}
+
+	loaded, err := LoadTablebase(filename)
+	if err != nil {
+		t.Fatalf("LoadTablebase() error = %v", err)
+	}
+	if loaded.entries != Default().entries {
+		t.Errorf("loaded tablebase differs from the saved one")
+	}
+
+	if err := os.WriteFile(filename, []byte{1, 2, 3}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTablebase(filename); err == nil {
+		t.Errorf("LoadTablebase() accepted a truncated file")
+	}
+}
+
+func TestLoadOrBuild(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "tictactoe.tb")
+	if got := loadOrBuild(filename); got.entries != Default().entries {
+		t.Errorf("loadOrBuild() without a file differs from Build()")
+	}
+
+	// A saved file is used as is, so an entry dropped from it stays dropped
+	saved := *Default()
+	saved.entries[0] = 0
+	if err := saved.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+	if got := loadOrBuild(filename); got.entries[0] != 0 {
+		t.Errorf("loadOrBuild() rebuilt instead of loading %s", filename)
+	}
+
+	if err := os.WriteFile(filename, []byte{1, 2, 3}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := loadOrBuild(filename); got.entries != Default().entries {
+		t.Errorf("loadOrBuild() with a truncated file differs from Build()")
+	}
+}
@@ -0,0 +1,113 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+)
+
+// Decision is the state of a sequential test
+type Decision int
+
+const (
+	Continue Decision = iota
+	AcceptH0
+	AcceptH1
+)
+
+func (d Decision) String() string {
+	switch d {
+	case AcceptH0:
+		return "H0 accepted"
+	case AcceptH1:
+		return "H1 accepted"
+	default:
+		return "continue"
+	}
+}
+
+// SPRT tests H0: the Elo difference is Elo0 against H1: it is Elo1, with
+// false-positive rate Alpha and false-negative rate Beta. With Elo0 = 0 and
+// Elo1 > 0, accepting H1 means the first agent is stronger and accepting H0
+// means it is not (the agents are equal, or it is weaker).
+type SPRT struct {
+	Elo0, Elo1  float64
+	Alpha, Beta float64
+}
+
+// DefaultSPRT separates a 50 Elo improvement from none at 5% error rates.
+// Tic-tac-toe agents are either close to perfect or far from it, so finer
+// bounds mostly cost games.
+func DefaultSPRT() SPRT {
+	return SPRT{Elo0: 0, Elo1: 50, Alpha: 0.05, Beta: 0.05}
+}
+
+// Bounds returns the log-likelihood ratios at which H0 and H1 are accepted
+func (s SPRT) Bounds() (lower, upper float64) {
+	return math.Log(s.Beta / (1 - s.Alpha)), math.Log((1 - s.Beta) / s.Alpha)
+}
+
+// LLR returns the generalized log-likelihood ratio of H1 against H0 for a
+// win/draw/loss record, using the normal approximation to the trinomial
+// score distribution. Counts are regularized with half a game of each outcome
+// so the variance is never zero, e.g. after a run of draws.
+func (s SPRT) LLR(wins, draws, losses int) float64 {
+	w, d, l := float64(wins)+0.5, float64(draws)+0.5, float64(losses)+0.5
+	n := w + d + l
+	mean := (w + 0.5*d) / n
+	variance := (w*(1-mean)*(1-mean) + d*(0.5-mean)*(0.5-mean) + l*mean*mean) / n
+	s0, s1 := ExpectedScore(s.Elo0), ExpectedScore(s.Elo1)
+	return n * (s1 - s0) * (2*mean - s0 - s1) / (2 * variance)
+}
+
+// Test returns the decision for a win/draw/loss record
+func (s SPRT) Test(wins, draws, losses int) Decision {
+	lower, upper := s.Bounds()
+	switch llr := s.LLR(wins, draws, losses); {
+	case llr >= upper:
+		return AcceptH1
+	case llr <= lower:
+		return AcceptH0
+	default:
+		return Continue
+	}
+}
+
+// Validate checks that the hypotheses and error rates make sense
+func (s SPRT) Validate() error {
+	if s.Elo1 <= s.Elo0 {
+		return fmt.Errorf("elo1 (%g) must be greater than elo0 (%g)", s.Elo1, s.Elo0)
+	}
+	if s.Alpha <= 0 || s.Alpha >= 0.5 || s.Beta <= 0 || s.Beta >= 0.5 {
+		return fmt.Errorf("alpha (%g) and beta (%g) must be between 0 and 0.5", s.Alpha, s.Beta)
+	}
+	return nil
+}
+
+// Result is the outcome of a sequential match
+type Result struct {
+	Wins, Draws, Losses int
+	LLR                 float64
+	Decision            Decision
+}
+
+// Run plays batches of games with play, which returns the first agent's wins,
+// draws and losses for a batch, until the test reaches a decision or maxGames
+// have been played. The decision is Continue if the limit was reached first.
+func (s SPRT) Run(play func(games int) (wins, draws, losses int), batch, maxGames int) Result {
+	var r Result
+	for r.Wins+r.Draws+r.Losses < maxGames {
+		n := batch
+		if remaining := maxGames - (r.Wins + r.Draws + r.Losses); n > remaining {
+			n = remaining
+		}
+		w, d, l := play(n)
+		r.Wins += w
+		r.Draws += d
+		r.Losses += l
+		if r.Decision = s.Test(r.Wins, r.Draws, r.Losses); r.Decision != Continue {
+			break
+		}
+	}
+	r.LLR = s.LLR(r.Wins, r.Draws, r.Losses)
+	return r
+}
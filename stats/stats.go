@@ -0,0 +1,89 @@
+// Package stats puts error bars on match results: Wilson and bootstrap
+// confidence intervals, Elo estimates, and a sequential probability ratio
+// test that plays only as many games as are needed to separate two agents.
+package stats
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Interval is a confidence interval [Low, High]
+type Interval struct {
+	Low, High float64
+}
+
+func (i Interval) String() string {
+	return fmt.Sprintf("[%.3f, %.3f]", i.Low, i.High)
+}
+
+// zScore returns the two-sided normal quantile for a confidence level, e.g.
+// 1.96 for 0.95
+func zScore(confidence float64) float64 {
+	return math.Sqrt2 * math.Erfinv(confidence)
+}
+
+// Wilson returns the Wilson score interval for a proportion of successes out
+// of n trials. It stays inside [0, 1] and behaves well near 0% and 100%,
+// unlike the normal approximation.
+func Wilson(successes, n int, confidence float64) Interval {
+	if n == 0 {
+		return Interval{0, 1}
+	}
+	z := zScore(confidence)
+	p := float64(successes) / float64(n)
+	nf := float64(n)
+	denominator := 1 + z*z/nf
+	center := (p + z*z/(2*nf)) / denominator
+	margin := z * math.Sqrt(p*(1-p)/nf+z*z/(4*nf*nf)) / denominator
+	return Interval{math.Max(0, center-margin), math.Min(1, center+margin)}
+}
+
+// Score returns the match score: a win counts 1, a draw 0.5 and a loss 0
+func Score(wins, draws, losses int) float64 {
+	n := wins + draws + losses
+	if n == 0 {
+		return 0.5
+	}
+	return (float64(wins) + 0.5*float64(draws)) / float64(n)
+}
+
+// Bootstrap returns a percentile bootstrap interval for the mean match score,
+// resampling the individual games
+func Bootstrap(wins, draws, losses int, confidence float64, resamples int, rng *rand.Rand) Interval {
+	n := wins + draws + losses
+	if n == 0 {
+		return Interval{0, 1}
+	}
+	means := make([]float64, resamples)
+	for r := range means {
+		total := 0.0
+		for i := 0; i < n; i++ {
+			switch game := rng.Intn(n); {
+			case game < wins:
+				total++
+			case game < wins+draws:
+				total += 0.5
+			}
+		}
+		means[r] = total / float64(n)
+	}
+	sort.Float64s(means)
+	tail := (1 - confidence) / 2
+	low := means[int(math.Floor(tail*float64(resamples-1)))]
+	high := means[int(math.Ceil((1-tail)*float64(resamples-1)))]
+	return Interval{low, high}
+}
+
+// Elo converts an expected score into an Elo rating difference. Scores of 0
+// and 1 map to infinities.
+func Elo(score float64) float64 {
+	return 400 * math.Log10(score/(1-score))
+}
+
+// ExpectedScore converts an Elo difference into an expected score
+func ExpectedScore(elo float64) float64 {
+	return 1 / (1 + math.Pow(10, -elo/400))
+}
@@ -0,0 +1,141 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func near(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func TestWilson(t *testing.T) {
+	tests := []struct {
+		successes, n int
+		want         Interval
+	}{
+		{8, 10, Interval{0.4902, 0.9433}},
+		{0, 10, Interval{0, 0.2775}},
+		{10, 10, Interval{0.7225, 1}},
+		{500, 1000, Interval{0.4691, 0.5309}},
+		{0, 0, Interval{0, 1}},
+	}
+	for _, tt := range tests {
+		got := Wilson(tt.successes, tt.n, 0.95)
+		if !near(got.Low, tt.want.Low, 1e-4) || !near(got.High, tt.want.High, 1e-4) {
+			t.Errorf("Wilson(%d, %d) = %v, want %v", tt.successes, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	small := Bootstrap(6, 2, 2, 0.95, 2000, rng)
+	large := Bootstrap(600, 200, 200, 0.95, 2000, rng)
+	score := Score(600, 200, 200)
+
+	for _, interval := range []Interval{small, large} {
+		if score < interval.Low || score > interval.High {
+			t.Errorf("Bootstrap() = %v does not contain the score %v", interval, score)
+		}
+	}
+	if large.High-large.Low >= small.High-small.Low {
+		t.Errorf("interval did not shrink with more games: %v vs %v", large, small)
+	}
+	// The normal approximation gives a half-width of about 0.024 here
+	if halfWidth := (large.High - large.Low) / 2; !near(halfWidth, 0.024, 0.004) {
+		t.Errorf("Bootstrap() half-width = %v, want about 0.024", halfWidth)
+	}
+}
+
+func TestElo(t *testing.T) {
+	if got := Elo(0.5); !near(got, 0, 1e-9) {
+		t.Errorf("Elo(0.5) = %v, want 0", got)
+	}
+	for _, elo := range []float64{-200, 50, 400} {
+		if got := Elo(ExpectedScore(elo)); !near(got, elo, 1e-9) {
+			t.Errorf("Elo(ExpectedScore(%v)) = %v", elo, got)
+		}
+	}
+	if got := ExpectedScore(400); !near(got, 10.0/11, 1e-9) {
+		t.Errorf("ExpectedScore(400) = %v, want 10/11", got)
+	}
+}
+
+// simulate returns a play function drawing games with the given win and draw
+// probabilities
+func simulate(rng *rand.Rand, win, draw float64) func(int) (int, int, int) {
+	return func(games int) (wins, draws, losses int) {
+		for i := 0; i < games; i++ {
+			switch r := rng.Float64(); {
+			case r < win:
+				wins++
+			case r < win+draw:
+				draws++
+			default:
+				losses++
+			}
+		}
+		return
+	}
+}
+
+func TestSPRTDecisions(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := DefaultSPRT()
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// About +140 Elo: clearly stronger
+	stronger := s.Run(simulate(rng, 0.5, 0.3), 10, 100000)
+	if stronger.Decision != AcceptH1 {
+		t.Errorf("stronger agent: %+v, want H1 accepted", stronger)
+	}
+
+	// Equal agents with many draws, as in tic-tac-toe
+	equal := s.Run(simulate(rng, 0.1, 0.8), 10, 100000)
+	if equal.Decision != AcceptH0 {
+		t.Errorf("equal agents: %+v, want H0 accepted", equal)
+	}
+
+	// A game limit stops the test without a decision
+	limited := s.Run(simulate(rng, 0.3, 0.4), 10, 20)
+	if games := limited.Wins + limited.Draws + limited.Losses; games != 20 || limited.Decision != Continue {
+		t.Errorf("limited run: %+v, want 20 games and no decision", limited)
+	}
+}
+
+func TestSPRTErrorRate(t *testing.T) {
+	// Equal agents must rarely be declared different: the false-positive rate
+	// should be close to alpha
+	rng := rand.New(rand.NewSource(2))
+	s := DefaultSPRT()
+	falsePositives, runs := 0, 300
+	for i := 0; i < runs; i++ {
+		if s.Run(simulate(rng, 0.3, 0.4), 10, 100000).Decision == AcceptH1 {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / float64(runs); rate > 2*s.Alpha {
+		t.Errorf("false-positive rate = %v, want at most about %v", rate, s.Alpha)
+	}
+}
+
+func TestSPRTValidate(t *testing.T) {
+	tests := []struct {
+		sprt    SPRT
+		wantErr bool
+	}{
+		{DefaultSPRT(), false},
+		{SPRT{Elo0: 10, Elo1: 0, Alpha: 0.05, Beta: 0.05}, true},
+		{SPRT{Elo0: 0, Elo1: 10, Alpha: 0, Beta: 0.05}, true},
+		{SPRT{Elo0: 0, Elo1: 10, Alpha: 0.05, Beta: 0.6}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.sprt.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.sprt, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Package sweep searches tabular-agent hyperparameters: it builds candidate
+// Options by grid or random search, trains them in parallel, and ranks them
+// by a caller-supplied score. Successive halving spends most of the training
+// budget on the candidates that look best early on.
+package sweep
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/jpotts18/tictactoe/agent"
+)
+
+// Space lists the values to search for each option. Grid search takes every
+// combination; random search draws uniformly between the smallest and largest
+// value of each list. MinEpsilon is kept at its default.
+type Space struct {
+	Epsilon []float64
+	Decay   []float64
+	Alpha   []float64
+	Gamma   []float64
+}
+
+// DefaultSpace covers the region around DefaultOptions: 36 grid points
+func DefaultSpace() Space {
+	return Space{
+		Epsilon: []float64{0.3, 0.6, 0.9},
+		Decay:   []float64{0.9999, 0.99995},
+		Alpha:   []float64{0.05, 0.1, 0.3},
+		Gamma:   []float64{0.9, 0.99},
+	}
+}
+
+// Grid returns every combination of the values in space
+func Grid(space Space) []agent.Options {
+	var candidates []agent.Options
+	for _, epsilon := range space.Epsilon {
+		for _, decay := range space.Decay {
+			for _, alpha := range space.Alpha {
+				for _, gamma := range space.Gamma {
+					opts := agent.DefaultOptions()
+					opts.Epsilon, opts.Decay, opts.Alpha, opts.Gamma = epsilon, decay, alpha, gamma
+					opts.MinEpsilon = math.Min(opts.MinEpsilon, epsilon)
+					candidates = append(candidates, opts)
+				}
+			}
+		}
+	}
+	return candidates
+}
+
+// uniform draws between the smallest and largest of values
+func uniform(values []float64, rng *rand.Rand) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low = math.Min(low, v)
+		high = math.Max(high, v)
+	}
+	return low + rng.Float64()*(high-low)
+}
+
+// Random draws n candidates from the ranges covered by space
+func Random(space Space, n int, rng *rand.Rand) []agent.Options {
+	candidates := make([]agent.Options, n)
+	for i := range candidates {
+		opts := agent.DefaultOptions()
+		opts.Epsilon = uniform(space.Epsilon, rng)
+		opts.Decay = uniform(space.Decay, rng)
+		opts.Alpha = uniform(space.Alpha, rng)
+		opts.Gamma = uniform(space.Gamma, rng)
+		opts.MinEpsilon = math.Min(opts.MinEpsilon, opts.Epsilon)
+		candidates[i] = opts
+	}
+	return candidates
+}
+
+// Trial is one candidate configuration and its trained model
+type Trial struct {
+	ID      int
+	Options agent.Options
+	Games   int // training games played so far
+	Score   float64
+	Agent   agent.LearningAgent
+}
+
+// Runner trains and scores candidates. New, Train and Score are called
+// concurrently from Workers goroutines, each on its own agent.
+type Runner struct {
+	New     func(opts agent.Options) agent.LearningAgent
+	Train   func(a agent.Agent, games int)
+	Score   func(a agent.Agent) float64
+	Workers int
+}
+
+// run trains every trial for a further games games and rescores it
+func (r Runner) run(trials []*Trial, games int) {
+	workers := r.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	jobs := make(chan *Trial)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for trial := range jobs {
+				r.Train(trial.Agent, games)
+				trial.Games += games
+				trial.Score = r.Score(trial.Agent)
+			}
+		}()
+	}
+	for _, trial := range trials {
+		jobs <- trial
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func (r Runner) newTrials(candidates []agent.Options) []*Trial {
+	trials := make([]*Trial, len(candidates))
+	for i, opts := range candidates {
+		trials[i] = &Trial{ID: i + 1, Options: opts, Agent: r.New(opts)}
+	}
+	return trials
+}
+
+// rank sorts trials in place by descending score, breaking ties by ID
+func rank(trials []*Trial) {
+	sort.SliceStable(trials, func(i, j int) bool {
+		if trials[i].Score != trials[j].Score {
+			return trials[i].Score > trials[j].Score
+		}
+		return trials[i].ID < trials[j].ID
+	})
+}
+
+func values(trials []*Trial) []Trial {
+	result := make([]Trial, len(trials))
+	for i, trial := range trials {
+		result[i] = *trial
+	}
+	return result
+}
+
+// Evaluate trains every candidate for games games and returns them ranked,
+// best first
+func (r Runner) Evaluate(candidates []agent.Options, games int) []Trial {
+	trials := r.newTrials(candidates)
+	r.run(trials, games)
+	rank(trials)
+	return values(trials)
+}
+
+// SuccessiveHalving trains every candidate for games games, keeps the best
+// 1/eta, trains the survivors until they have played eta times as many games,
+// and repeats until one is left. Survivors keep training the same model rather
+// than starting over. The result lists the survivors of each round above the
+// trials eliminated in it, since they were measured after more training.
+func (r Runner) SuccessiveHalving(candidates []agent.Options, games, eta int) []Trial {
+	if eta < 2 {
+		eta = 2
+	}
+	survivors := r.newTrials(candidates)
+	var eliminated []*Trial
+	for budget := games; len(survivors) > 0; budget *= eta {
+		r.run(survivors, budget-survivors[0].Games)
+		rank(survivors)
+		if len(survivors) == 1 {
+			break
+		}
+		keep := (len(survivors) + eta - 1) / eta
+		eliminated = append(append([]*Trial{}, survivors[keep:]...), eliminated...)
+		survivors = survivors[:keep]
+	}
+	return values(append(survivors, eliminated...))
+}
+
+// WriteTable writes trials as an aligned, ranked table
+func WriteTable(w io.Writer, trials []Trial) error {
+	if _, err := fmt.Fprintf(w, "%-5s %-5s %-8s %-11s %-9s %-6s %-6s %-8s %s\n",
+		"Rank", "Trial", "Epsilon", "MinEpsilon", "Decay", "Alpha", "Gamma", "Games", "Score"); err != nil {
+		return err
+	}
+	for i, trial := range trials {
+		o := trial.Options
+		if _, err := fmt.Fprintf(w, "%-5d %-5d %-8.3f %-11.3f %-9.6g %-6.3f %-6.3f %-8d %.4f\n",
+			i+1, trial.ID, o.Epsilon, o.MinEpsilon, o.Decay, o.Alpha, o.Gamma, trial.Games, trial.Score); err != nil {
+			return err
+		}
+	}
+	return nil
+}
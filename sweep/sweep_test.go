@@ -0,0 +1,120 @@
+package sweep
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jpotts18/tictactoe/agent"
+)
+
+func TestGrid(t *testing.T) {
+	candidates := Grid(DefaultSpace())
+	if len(candidates) != 36 {
+		t.Fatalf("Grid() returned %d candidates, want 36", len(candidates))
+	}
+	seen := make(map[agent.Options]bool)
+	for _, opts := range candidates {
+		if err := opts.Validate(); err != nil {
+			t.Errorf("invalid candidate %v: %v", opts, err)
+		}
+		if seen[opts] {
+			t.Errorf("duplicate candidate %v", opts)
+		}
+		seen[opts] = true
+	}
+}
+
+func TestRandom(t *testing.T) {
+	space := DefaultSpace()
+	candidates := Random(space, 50, rand.New(rand.NewSource(1)))
+	if len(candidates) != 50 {
+		t.Fatalf("Random() returned %d candidates, want 50", len(candidates))
+	}
+	for _, opts := range candidates {
+		if opts.Alpha < 0.05 || opts.Alpha > 0.3 || opts.Epsilon < 0.3 || opts.Epsilon > 0.9 {
+			t.Errorf("candidate %v is outside the space", opts)
+		}
+		if err := opts.Validate(); err != nil {
+			t.Errorf("invalid candidate %v: %v", opts, err)
+		}
+	}
+}
+
+// epsilonRunner scores a Q-learning agent by its untouched initial epsilon,
+// lowest best, so the ranking is known in advance. It only counts training
+// games instead of playing them.
+func epsilonRunner(games *int64) Runner {
+	return Runner{
+		New: func(opts agent.Options) agent.LearningAgent {
+			return agent.NewQAgentWithOptions(1, opts)
+		},
+		Train: func(a agent.Agent, n int) {
+			atomic.AddInt64(games, int64(n))
+		},
+		Score: func(a agent.Agent) float64 {
+			return 1 - a.(*agent.QAgent).Epsilon()
+		},
+		Workers: 4,
+	}
+}
+
+func TestEvaluateRanks(t *testing.T) {
+	var games int64
+	candidates := []agent.Options{agent.DefaultOptions(), agent.DefaultOptions(), agent.DefaultOptions()}
+	candidates[0].Epsilon = 0.5
+	candidates[1].Epsilon = 0.2
+	candidates[2].Epsilon = 0.8
+
+	trials := epsilonRunner(&games).Evaluate(candidates, 100)
+	if trials[0].ID != 2 || trials[1].ID != 1 || trials[2].ID != 3 {
+		t.Errorf("ranking = %d, %d, %d, want 2, 1, 3", trials[0].ID, trials[1].ID, trials[2].ID)
+	}
+	if games != 300 || trials[0].Games != 100 {
+		t.Errorf("trained %d games in total and %d for the best, want 300 and 100", games, trials[0].Games)
+	}
+}
+
+func TestSuccessiveHalving(t *testing.T) {
+	var games int64
+	var candidates []agent.Options
+	for i := 0; i < 8; i++ {
+		opts := agent.DefaultOptions()
+		opts.Epsilon = 0.1 * float64(i+1) // trial 1 is best
+		opts.MinEpsilon = 0
+		candidates = append(candidates, opts)
+	}
+
+	trials := epsilonRunner(&games).SuccessiveHalving(candidates, 100, 2)
+	if len(trials) != 8 {
+		t.Fatalf("SuccessiveHalving() returned %d trials, want 8", len(trials))
+	}
+	if trials[0].ID != 1 {
+		t.Errorf("best trial = %d, want 1", trials[0].ID)
+	}
+
+	// Rounds of 8, 4, 2 and 1 trials at 100, 200, 400 and 800 cumulative games
+	wantGames := []int{800, 400, 200, 200, 100, 100, 100, 100}
+	for i, trial := range trials {
+		if trial.Games != wantGames[i] {
+			t.Errorf("trial at rank %d played %d games, want %d", i+1, trial.Games, wantGames[i])
+		}
+	}
+	if games != 8*100+4*100+2*200+1*400 {
+		t.Errorf("trained %d games in total, want %d", games, 8*100+4*100+2*200+1*400)
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	var buf bytes.Buffer
+	trials := []Trial{{ID: 3, Options: agent.DefaultOptions(), Games: 1000, Score: 0.75}}
+	if err := WriteTable(&buf, trials); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "1     3 ") || !strings.HasSuffix(lines[1], "0.7500") {
+		t.Errorf("WriteTable() =\n%s", buf.String())
+	}
+}
@@ -0,0 +1,165 @@
+package training
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+
+	"github.com/jpotts18/tictactoe/agent"
+	"github.com/jpotts18/tictactoe/reward"
+)
+
+// Stage is one step of a curriculum. The learner trains against Opponent, an
+// agent.New spec, until over the last Window games it has won at
+// least MinWins of them and avoided losing at least MinNonLosses. The last
+// stage is never left, so its thresholds are unused.
+type Stage struct {
+	Opponent     string  `json:"opponent"`
+	Window       int     `json:"window"`
+	MinWins      float64 `json:"min_wins,omitempty"`
+	MinNonLosses float64 `json:"min_non_losses,omitempty"`
+}
+
+func (s Stage) String() string {
+	return fmt.Sprintf("%s (window=%d min_wins=%g min_non_losses=%g)", s.Opponent, s.Window, s.MinWins, s.MinNonLosses)
+}
+
+// Curriculum is a sequence of increasingly strong opponents
+type Curriculum struct {
+	Stages []Stage `json:"stages"`
+}
+
+// DefaultCurriculum moves from random play through the heuristic agent and a
+// shallow minimax to perfect play
+func DefaultCurriculum() Curriculum {
+	return Curriculum{Stages: []Stage{
+		{Opponent: "random", Window: 1000, MinWins: 0.6, MinNonLosses: 0.8},
+		{Opponent: "heuristic", Window: 1000, MinNonLosses: 0.7},
+		{Opponent: "minimax:depth=2", Window: 1000, MinNonLosses: 0.8},
+		{Opponent: "perfect", Window: 1000},
+	}}
+}
+
+// LoadCurriculum reads a curriculum from a JSON file
+func LoadCurriculum(filename string) (Curriculum, error) {
+	var c Curriculum
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("%s: %v", filename, err)
+	}
+	return c, c.Validate()
+}
+
+// Validate checks that every stage names a known opponent and has a usable
+// window and thresholds
+func (c Curriculum) Validate() error {
+	if len(c.Stages) == 0 {
+		return fmt.Errorf("curriculum has no stages")
+	}
+	for i, s := range c.Stages {
+		if _, err := agent.New(s.Opponent, 2); err != nil {
+			return fmt.Errorf("stage %d: %v", i+1, err)
+		}
+		if s.Window <= 0 {
+			return fmt.Errorf("stage %d: window must be positive, got %d", i+1, s.Window)
+		}
+		if s.MinWins < 0 || s.MinWins > 1 || s.MinNonLosses < 0 || s.MinNonLosses > 1 {
+			return fmt.Errorf("stage %d: thresholds must be in [0, 1]", i+1)
+		}
+	}
+	return nil
+}
+
+// Schedule tracks a learner's progress through a curriculum
+type Schedule struct {
+	stages    []Stage
+	opponents []agent.Agent
+	stage     int
+	window    []int // results of the current stage's last games: 1, 0 or -1
+	next      int   // where the next result goes once the window is full
+	games     int   // games played, which alternate the learner's seat
+}
+
+// Start builds the opponents of c and returns a schedule at its first stage
+func (c Curriculum) Start() (*Schedule, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	s := &Schedule{stages: c.Stages}
+	for _, stage := range c.Stages {
+		opponent, _ := agent.New(stage.Opponent, 2)
+		s.opponents = append(s.opponents, opponent)
+	}
+	return s, nil
+}
+
+// Seed fixes the random source of every opponent that has one, each with its
+// own seed drawn from seed
+func (s *Schedule) Seed(seed int64) {
+	rng := rand.New(rand.NewSource(seed))
+	for _, opponent := range s.opponents {
+		if seeder, ok := opponent.(agent.Seeder); ok {
+			seeder.Seed(rng.Int63())
+		}
+	}
+}
+
+// Stage returns the index of the current stage
+func (s *Schedule) Stage() int {
+	return s.stage
+}
+
+// Current returns the current stage
+func (s *Schedule) Current() Stage {
+	return s.stages[s.stage]
+}
+
+// Opponent returns the agent the learner currently trains against
+func (s *Schedule) Opponent() agent.Agent {
+	return s.opponents[s.stage]
+}
+
+// Record adds the learner's result in one game (1 win, 0 draw, -1 loss) to
+// the window and reports whether that promoted it to the next stage
+func (s *Schedule) Record(result int) bool {
+	stage := s.Current()
+	if len(s.window) < stage.Window {
+		s.window = append(s.window, result)
+	} else {
+		s.window[s.next] = result
+		s.next = (s.next + 1) % stage.Window
+	}
+	if s.stage == len(s.stages)-1 || len(s.window) < stage.Window {
+		return false
+	}
+
+	wins, nonLosses := 0, 0
+	for _, r := range s.window {
+		if r > 0 {
+			wins++
+		}
+		if r >= 0 {
+			nonLosses++
+		}
+	}
+	n := float64(stage.Window)
+	if float64(wins)/n < stage.MinWins || float64(nonLosses)/n < stage.MinNonLosses {
+		return false
+	}
+	s.stage++
+	s.window, s.next = nil, 0
+	return true
+}
+
+// Play plays one game of learner against the current opponent, learning from
+// rewards, and reports whether the result promoted the learner. Seated
+// learners play X and O in turn.
+func (s *Schedule) Play(learner agent.Agent, rewards reward.Function) bool {
+	result := Match(learner, s.Opponent(), s.games, rewards)
+	s.games++
+	return s.Record(result)
+}
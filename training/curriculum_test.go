@@ -0,0 +1,117 @@
+package training
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestScheduleRecord(t *testing.T) {
+	c := Curriculum{Stages: []Stage{
+		{Opponent: "random", Window: 4, MinWins: 0.5, MinNonLosses: 0.75},
+		{Opponent: "minimax:depth=2", Window: 2, MinNonLosses: 1},
+		{Opponent: "perfect", Window: 2},
+	}}
+	s, err := c.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		result    int
+		promoted  bool
+		wantStage int
+	}{
+		{1, false, 0}, // the window is not full yet
+		{1, false, 0},
+		{-1, false, 0},
+		{-1, false, 0}, // 2 wins but only 2 of 4 not lost
+		{0, false, 0},  // the oldest win drops out: 1 win in 4
+		{1, false, 0},  // and so does the other
+		{1, true, 1},   // a loss drops out: 2 wins, 3 not lost
+		{0, false, 1},  // a fresh window for the new stage
+		{-1, false, 1},
+		{0, false, 1}, // the loss is still in the window
+		{0, true, 2},
+		{1, false, 2}, // the last stage is never left
+		{1, false, 2},
+		{1, false, 2},
+	}
+	for i, step := range steps {
+		if promoted := s.Record(step.result); promoted != step.promoted || s.Stage() != step.wantStage {
+			t.Fatalf("step %d: Record(%d) = %v at stage %d, want %v at stage %d",
+				i, step.result, promoted, s.Stage(), step.promoted, step.wantStage)
+		}
+	}
+	if s.Current().Opponent != "perfect" {
+		t.Errorf("Current() = %v, want the perfect stage", s.Current())
+	}
+}
+
+func TestCurriculumValidate(t *testing.T) {
+	if err := DefaultCurriculum().Validate(); err != nil {
+		t.Errorf("DefaultCurriculum() is invalid: %v", err)
+	}
+	invalid := []Curriculum{
+		{},
+		{Stages: []Stage{{Opponent: "grandmaster", Window: 10}}},
+		{Stages: []Stage{{Opponent: "random"}}},
+		{Stages: []Stage{{Opponent: "random", Window: 10, MinWins: 1.5}}},
+	}
+	for _, c := range invalid {
+		if err := c.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, want an error", c)
+		}
+	}
+}
+
+func TestLoadCurriculum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "curriculum.json")
+	data := `{"stages": [
+		{"opponent": "random", "window": 200, "min_wins": 0.7},
+		{"opponent": "minimax:depth=3", "window": 500, "min_non_losses": 0.9},
+		{"opponent": "perfect", "window": 500}
+	]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadCurriculum(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Curriculum{Stages: []Stage{
+		{Opponent: "random", Window: 200, MinWins: 0.7},
+		{Opponent: "minimax:depth=3", Window: 500, MinNonLosses: 0.9},
+		{Opponent: "perfect", Window: 500},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadCurriculum() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSchedulePlay(t *testing.T) {
+	// The scripted learner always wins the race for the top row against a
+	// random opponent often enough to be promoted
+	c := Curriculum{Stages: []Stage{
+		{Opponent: "random", Window: 20, MinNonLosses: 0.1},
+		{Opponent: "perfect", Window: 20},
+	}}
+	s, err := c.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	learner := &seatRecorder{scripted: scripted{preferences: []int{0, 1, 2, 3, 4, 5, 6, 7, 8}}, seats: make(map[int]int)}
+	for i := 0; i < 1000 && s.Stage() == 0; i++ {
+		s.Play(learner, rewards)
+	}
+	if s.Stage() != 1 {
+		t.Errorf("Stage() = %d after 1000 games, want the learner promoted to 1", s.Stage())
+	}
+	if len(learner.learned) == 0 {
+		t.Errorf("the learner was not given any transitions")
+	}
+	if learner.seats[1] == 0 || learner.seats[2] == 0 {
+		t.Errorf("the learner moved as X %d times and as O %d times, want both seats", learner.seats[1], learner.seats[2])
+	}
+}
@@ -0,0 +1,157 @@
+// Package training plays games between agents and turns them into learning
+// experience. Players alternate, so a move's outcome is only known once the
+// opponent has replied: the manager holds each player's transition until
+// then and delivers (s, a, r, s') from that player's own perspective. When
+// the game ends both players receive their final reward, including the one
+// who lost.
+package training
+
+import (
+	"github.com/jpotts18/tictactoe/agent"
+	"github.com/jpotts18/tictactoe/game"
+	"github.com/jpotts18/tictactoe/reward"
+)
+
+// pending is a move whose outcome is not yet known
+type pending struct {
+	before game.Board
+	state  string
+	action int
+}
+
+// Manager runs the turns of one game between player 1 and player 2. A nil
+// reward function plays without learning.
+type Manager struct {
+	rewards reward.Function
+	agents  [3]agent.Agent
+	pending [3]*pending
+}
+
+// NewManager returns a manager for a game between x (player 1) and o
+// (player 2). x and o may be the same agent.
+func NewManager(x, o agent.Agent, rewards reward.Function) *Manager {
+	return &Manager{rewards: rewards, agents: [3]agent.Agent{nil, x, o}}
+}
+
+// Move plays player's turn on board and returns the move. First it completes
+// player's previous transition: the next state is board as the opponent left
+// it, and the reward is computed for player from its previous move to now.
+// A move that is not an empty cell is not played and Move returns -1.
+func (m *Manager) Move(board *game.Board, player int) int {
+	a := m.agents[player]
+	if p := m.pending[player]; p != nil && m.rewards != nil {
+		a.Learn(p.state, p.action, m.rewards.Reward(&p.before, board, player), a.GetStateKey(board))
+	}
+	m.pending[player] = nil
+
+	before := *board
+	state := a.GetStateKey(board)
+	move := a.GetMove(board, player)
+	if move < 0 || move > 8 || !board.IsEmpty(move) {
+		return -1
+	}
+	m.pending[player] = &pending{before, state, move}
+	board.MakeMove(move, player)
+	return move
+}
+
+// End delivers each player's final transition, with newState "", once the
+// game on board is over
+func (m *Manager) End(board *game.Board) {
+	for player := 1; player <= 2; player++ {
+		p := m.pending[player]
+		m.pending[player] = nil
+		if p == nil || m.rewards == nil {
+			continue
+		}
+		m.agents[player].Learn(p.state, p.action, m.rewards.Reward(&p.before, board, player), "")
+	}
+}
+
+// Game plays one game between x (player 1) and o (player 2), with first
+// moving first, and returns the winner, or 0 for a draw. The agents learn
+// from rewards unless it is nil.
+func Game(x, o agent.Agent, first int, rewards reward.Function) int {
+	m := NewManager(x, o, rewards)
+	board := game.NewBoard()
+	for player := first; ; player = 3 - player {
+		if move := m.Move(board, player); move < 0 {
+			// An agent with no move to make concedes
+			m.End(board)
+			return 3 - player
+		}
+		if over, winner := board.IsGameOver(); over {
+			m.End(board)
+			return winner
+		}
+	}
+}
+
+// Play plays numGames games between agent1 and agent2 and returns agent1's
+// wins, draws and losses. If both agents can change seats, agent1 plays X in
+// even games and O in odd ones, so it is tested from both sides; it is left as player 1 and agent2 as player 2.
+// Otherwise agent1 is always player 1. The agents learn from rewards unless
+// it is nil.
+func Play(agent1, agent2 agent.Agent, numGames int, rewards reward.Function) (wins, draws, losses int) {
+	for i := 0; i < numGames; i++ {
+		switch Match(agent1, agent2, i, rewards) {
+		case 1:
+			wins++
+		case 0:
+			draws++
+		default:
+			losses++
+		}
+	}
+	return
+}
+
+// Match plays game number i of a Play series and returns agent1's result: 1
+// for a win, 0 for a draw and -1 for a loss. The first mover switches every
+// two games, so over four games each seat moves first as X and as O. Callers
+// that play one game at a time pass a running count to keep this rotation.
+func Match(agent1, agent2 agent.Agent, i int, rewards reward.Function) int {
+	first := 1 + i/2%2
+	x, o, seat := agent1, agent2, 1
+	seated1, ok1 := agent1.(agent.SeatedAgent)
+	seated2, ok2 := agent2.(agent.SeatedAgent)
+	if ok1 && ok2 {
+		defer func() {
+			seated2.SetPlayer(2)
+			seated1.SetPlayer(1)
+		}()
+		if i%2 == 1 {
+			x, o, seat = agent2, agent1, 2
+		}
+		// agent1 and agent2 may be the same instance, which then keeps
+		// agent1's seat
+		x.(agent.SeatedAgent).SetPlayer(1)
+		o.(agent.SeatedAgent).SetPlayer(2)
+		seated1.SetPlayer(seat)
+	}
+	switch winner := Game(x, o, first, rewards); winner {
+	case 0:
+		return 0
+	case seat:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// Seats returns the two players of a self-play game for a. Agents that
+// implement agent.SelfPlayAgent get a separate view per seat; any other agent
+// plays both seats as one instance.
+func Seats(a agent.Agent) (x, o agent.Agent) {
+	if s, ok := a.(agent.SelfPlayAgent); ok {
+		return s.ForSeat(1), s.ForSeat(2)
+	}
+	return a, a
+}
+
+// SelfPlay plays numGames games of a against itself, learning from rewards,
+// with a view per seat where the agent supports it
+func SelfPlay(a agent.Agent, numGames int, rewards reward.Function) {
+	x, o := Seats(a)
+	Play(x, o, numGames, rewards)
+}
@@ -0,0 +1,256 @@
+package training
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/jpotts18/tictactoe/agent"
+	"github.com/jpotts18/tictactoe/game"
+	"github.com/jpotts18/tictactoe/reward"
+)
+
+type transition struct {
+	state    string
+	action   int
+	reward   float64
+	newState string
+}
+
+// scripted plays the first free cell of its preferences and records every
+// transition it learns from
+type scripted struct {
+	agent.BaseAgent
+	preferences []int
+	learned     []transition
+}
+
+func (s *scripted) GetMove(board *game.Board, player int) int {
+	for _, move := range s.preferences {
+		if board.IsEmpty(move) {
+			return move
+		}
+	}
+	return -1
+}
+
+func (s *scripted) Learn(state string, action int, reward float64, newState string) {
+	s.learned = append(s.learned, transition{state, action, reward, newState})
+}
+
+// fixed plays its moves in order whether or not they are legal, then -1
+type fixed struct {
+	agent.BaseAgent
+	moves []int
+}
+
+func (f *fixed) GetMove(board *game.Board, player int) int {
+	if len(f.moves) == 0 {
+		return -1
+	}
+	move := f.moves[0]
+	f.moves = f.moves[1:]
+	return move
+}
+
+func (f *fixed) Learn(state string, action int, reward float64, newState string) {}
+
+// attacker completes a line when it can and otherwise plays at random
+type attacker struct {
+	agent.BaseAgent
+}
+
+func (a *attacker) GetMove(board *game.Board, player int) int {
+	moves := board.GetAvailableMoves()
+	for _, move := range moves {
+		next := *board
+		next.MakeMove(move, player)
+		if over, winner := next.IsGameOver(); over && winner == player {
+			return move
+		}
+	}
+	return moves[rand.Intn(len(moves))]
+}
+
+func (a *attacker) Learn(state string, action int, reward float64, newState string) {}
+
+var rewards = reward.Scheme{Win: 1, Draw: 0.5, Loss: -2, Step: -0.01}
+
+func TestGameDeliversEachPlayersTransitions(t *testing.T) {
+	// X takes the top row while O plays the center and the left side
+	x := &scripted{preferences: []int{0, 1, 2, 3, 4, 5, 6, 7, 8}}
+	o := &scripted{preferences: []int{4, 3, 5, 6, 7, 8}}
+	if winner := Game(x, o, 1, rewards); winner != 1 {
+		t.Fatalf("Game() = %d, want X to win", winner)
+	}
+
+	wantX := []transition{
+		{"000000000", 0, rewards.Step, "100020000"},
+		{"100020000", 1, rewards.Step, "110220000"},
+		{"110220000", 2, rewards.Win, ""},
+	}
+	// O's next states are the boards after X's replies, and its last move is
+	// charged with the loss
+	wantO := []transition{
+		{"100000000", 4, rewards.Step, "110020000"},
+		{"110020000", 3, rewards.Loss, ""},
+	}
+	if !reflect.DeepEqual(x.learned, wantX) {
+		t.Errorf("X learned %v, want %v", x.learned, wantX)
+	}
+	if !reflect.DeepEqual(o.learned, wantO) {
+		t.Errorf("O learned %v, want %v", o.learned, wantO)
+	}
+}
+
+func TestGameDrawRewardsBothPlayers(t *testing.T) {
+	x := &scripted{preferences: []int{4, 0, 7, 5, 6, 8, 1, 2, 3}}
+	o := &scripted{preferences: []int{8, 1, 3, 2, 6, 0, 5, 7}}
+	if winner := Game(x, o, 1, rewards); winner != 0 {
+		t.Fatalf("Game() = %d, want a draw", winner)
+	}
+	for name, learned := range map[string][]transition{"X": x.learned, "O": o.learned} {
+		last := learned[len(learned)-1]
+		if last.reward != rewards.Draw || last.newState != "" {
+			t.Errorf("%s's final transition = %v, want the draw reward and newState \"\"", name, last)
+		}
+	}
+}
+
+func TestIllegalMoveConcedes(t *testing.T) {
+	tests := []struct {
+		name string
+		o    []int
+	}{
+		{"no move", nil},
+		{"occupied cell", []int{4}},
+		{"off the board", []int{9}},
+	}
+	for _, tt := range tests {
+		x := &scripted{preferences: []int{4, 0, 1, 2, 3, 5, 6, 7, 8}}
+		o := &fixed{moves: tt.o}
+		if winner := Game(x, o, 1, rewards); winner != 1 {
+			t.Errorf("%s: Game() = %d, want O to concede", tt.name, winner)
+		}
+		if last := x.learned[len(x.learned)-1]; last.newState != "" {
+			t.Errorf("%s: X's final transition = %v, want the game ended", tt.name, last)
+		}
+	}
+}
+
+func TestNilRewardsDoNotLearn(t *testing.T) {
+	x := &scripted{preferences: []int{0, 1, 2, 3, 4, 5, 6, 7, 8}}
+	o := &scripted{preferences: []int{4, 3, 5, 6, 7, 8}}
+	Game(x, o, 1, nil)
+	if len(x.learned)+len(o.learned) != 0 {
+		t.Errorf("agents learned %v and %v without rewards", x.learned, o.learned)
+	}
+}
+
+func TestPlayCountsFromAgent1(t *testing.T) {
+	// Whoever moves first completes their row first
+	x := &scripted{preferences: []int{0, 1, 2}}
+	o := &scripted{preferences: []int{8, 7, 6}}
+	wins, draws, losses := Play(x, o, 400, nil)
+	if wins+draws+losses != 400 || draws != 0 {
+		t.Fatalf("Play() = %d wins, %d draws, %d losses, want 400 decisive games", wins, draws, losses)
+	}
+	if wins < 150 || losses < 150 {
+		t.Errorf("Play() = %d wins and %d losses, want each agent to move first in half the games", wins, losses)
+	}
+}
+
+func TestMatchRotatesFirstMover(t *testing.T) {
+	// Whoever moves first completes their row first, so agent1 wins the games
+	// it opens: as X in game 0 and as O in game 3
+	a := &scripted{preferences: []int{0, 1, 2}}
+	b := &scripted{preferences: []int{8, 7, 6}}
+	a.Player, b.Player = 1, 2
+	want := []int{1, -1, -1, 1}
+	for i, result := range want {
+		if got := Match(a, b, i, nil); got != result {
+			t.Errorf("Match(%d) = %d, want %d", i, got, result)
+		}
+	}
+}
+
+func TestQLearningLearnsToBlock(t *testing.T) {
+	// Q-learning as O against an opponent that always completes a line. O
+	// only learns to block if the loss is charged to the move that allowed it.
+	q := agent.NewQAgent(2)
+	x := &attacker{}
+	for i := 0; i < 30000; i++ {
+		Game(x, q, 1, rewards)
+	}
+
+	q.SetEvaluating(true)
+	positions := []struct {
+		state string
+		block int
+	}{
+		{"110020000", 2},
+		{"010010200", 7},
+		{"100100020", 6},
+		{"000110200", 5},
+	}
+	for _, p := range positions {
+		if move := q.GetMove(game.FromStateString(p.state), 2); move != p.block {
+			t.Errorf("GetMove(%s) = %d, want the block at %d", p.state, move, p.block)
+		}
+	}
+	// An untrained agent loses about three games in four. The attacker can
+	// still stumble into a fork now and then.
+	losses := 0
+	for i := 0; i < 1000; i++ {
+		if Game(x, q, 1, nil) == 1 {
+			losses++
+		}
+	}
+	if losses > 100 {
+		t.Errorf("the trained agent lost %d of 1000 games, want at most 100", losses)
+	}
+}
+
+func TestSeats(t *testing.T) {
+	s := &scripted{}
+	if x, o := Seats(s); x != agent.Agent(s) || o != agent.Agent(s) {
+		t.Errorf("Seats() of an agent without seat views = %v, %v, want the agent twice", x, o)
+	}
+
+	q := agent.NewQAgent(1)
+	x, o := Seats(q)
+	if x == o || x == agent.Agent(q) {
+		t.Fatalf("Seats() = %p, %p, want two new views of %p", x, o, q)
+	}
+	SelfPlay(q, 200, rewards)
+	if q.TableSize() == 0 {
+		t.Errorf("TableSize() = 0 after self-play, want the seats to learn into the shared table")
+	}
+}
+
+// seatRecorder records the seat it is asked to move for
+type seatRecorder struct {
+	scripted
+	seats map[int]int
+}
+
+func (s *seatRecorder) GetMove(board *game.Board, player int) int {
+	if player != s.Player {
+		panic("GetMove called for a seat the agent does not hold")
+	}
+	s.seats[player]++
+	return s.scripted.GetMove(board, player)
+}
+
+func TestPlayAlternatesSeats(t *testing.T) {
+	a := &seatRecorder{scripted{preferences: []int{0, 1, 2, 3, 4, 5, 6, 7, 8}}, map[int]int{}}
+	b := &seatRecorder{scripted{preferences: []int{8, 7, 6, 5, 4, 3, 2, 1, 0}}, map[int]int{}}
+	a.Player, b.Player = 1, 2
+	Play(a, b, 100, nil)
+	if a.seats[1] == 0 || a.seats[2] == 0 || b.seats[1] == 0 || b.seats[2] == 0 {
+		t.Errorf("seats played: %v and %v, want both agents in both seats", a.seats, b.seats)
+	}
+	if a.Player != 1 || b.Player != 2 {
+		t.Errorf("after Play the agents are players %d and %d, want 1 and 2", a.Player, b.Player)
+	}
+}
@@ -0,0 +1,341 @@
+// Package tui is a full-screen terminal interface for playing against an
+// agent: arrow keys move a cursor, the last move and the winning line are
+// highlighted, and a side panel lists the move history.
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/jpotts18/tictactoe/agent"
+	"github.com/jpotts18/tictactoe/game"
+)
+
+// Move is one entry in the move history
+type Move struct {
+	Player   int
+	Position int
+}
+
+// Game holds the settings for a human-versus-agent game
+type Game struct {
+	Opponent    agent.Agent
+	HumanPlayer int
+	HumanFirst  bool
+
+	// Evaluate describes the position from the opponent's point of view for
+	// the status bar. It may be nil.
+	Evaluate func(board *game.Board, player int) string
+
+	// Hint returns an analysis of the position for the human, shown below the
+	// board when the user presses '?'. It may be nil.
+	Hint func(board *game.Board, player int) string
+
+	board   *game.Board
+	hint    string
+	history []Move
+	cursor  int
+	message string
+}
+
+type key int
+
+const (
+	keyNone key = iota
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyPlace
+	keyUndo
+	keyQuit
+	keyDigit
+	keyHint
+)
+
+// Run plays a game on the terminal attached to in, restoring the terminal
+// when the game ends or the user presses Ctrl-C. It returns the winner (0 for
+// a draw or an abandoned game).
+func (g *Game) Run(in *os.File, out io.Writer) (int, error) {
+	restore, err := makeRaw(in)
+	if err != nil {
+		return 0, err
+	}
+
+	// Restore the terminal even if the process is signalled from outside
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+			restore()
+			fmt.Fprint(out, "\033[?25h\r\n")
+			os.Exit(1)
+		case <-done:
+		}
+	}()
+	defer func() {
+		signal.Stop(signals)
+		close(done)
+		restore()
+		fmt.Fprint(out, "\033[?25h\r\n")
+	}()
+
+	fmt.Fprint(out, "\033[?25l")
+	return g.play(in, out), nil
+}
+
+// play runs the game loop over a stream of key presses
+func (g *Game) play(in io.Reader, out io.Writer) int {
+	g.board = game.NewBoard()
+	g.history = nil
+	g.cursor = 4
+	g.message = ""
+	g.hint = ""
+
+	if !g.HumanFirst {
+		g.agentMove()
+	}
+
+	reader := bufio.NewReader(in)
+	for {
+		g.render(out)
+		if over, winner := g.board.IsGameOver(); over {
+			return winner
+		}
+
+		k, digit := readKey(reader)
+		switch k {
+		case keyQuit:
+			g.message = "Game abandoned"
+			g.render(out)
+			return 0
+		case keyUp:
+			g.cursor = (g.cursor + 6) % 9
+		case keyDown:
+			g.cursor = (g.cursor + 3) % 9
+		case keyLeft:
+			g.cursor = g.cursor/3*3 + (g.cursor+2)%3
+		case keyRight:
+			g.cursor = g.cursor/3*3 + (g.cursor+1)%3
+		case keyDigit:
+			g.cursor = digit
+			g.humanMove()
+		case keyPlace:
+			g.humanMove()
+		case keyUndo:
+			g.undo()
+		case keyHint:
+			if g.hint == "" && g.Hint != nil {
+				g.hint = g.Hint(g.board, g.HumanPlayer)
+			} else {
+				g.hint = ""
+			}
+		}
+	}
+}
+
+func (g *Game) agentPlayer() int {
+	return g.HumanPlayer%2 + 1
+}
+
+func (g *Game) humanMove() {
+	if !g.board.MakeMove(g.cursor, g.HumanPlayer) {
+		g.message = "That square is taken"
+		return
+	}
+	g.history = append(g.history, Move{g.HumanPlayer, g.cursor})
+	g.message = ""
+	g.hint = ""
+
+	if over, _ := g.board.IsGameOver(); !over {
+		g.agentMove()
+	}
+}
+
+func (g *Game) agentMove() {
+	move := g.Opponent.GetMove(g.board, g.agentPlayer())
+	g.board.MakeMove(move, g.agentPlayer())
+	g.history = append(g.history, Move{g.agentPlayer(), move})
+}
+
+// undo takes back the human's last move and the agent's reply to it
+func (g *Game) undo() {
+	last := -1
+	for i := len(g.history) - 1; i >= 0; i-- {
+		if g.history[i].Player == g.HumanPlayer {
+			last = i
+			break
+		}
+	}
+	if last < 0 {
+		g.message = "Nothing to undo"
+		return
+	}
+
+	g.history = g.history[:last]
+	g.board = game.NewBoard()
+	for _, m := range g.history {
+		g.board.MakeMove(m.Position, m.Player)
+	}
+	g.message = "Move undone"
+}
+
+// readKey decodes one key press, including arrow-key escape sequences
+func readKey(r *bufio.Reader) (key, int) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return keyQuit, 0
+	}
+	switch {
+	case b == 3 || b == 'q' || b == 'Q':
+		return keyQuit, 0
+	case b == '\r' || b == ' ':
+		// A bare '\n' is left over from line-mode prompts, so only the raw
+		// Enter key ('\r') places a mark
+		return keyPlace, 0
+	case b == 'u' || b == 'U':
+		return keyUndo, 0
+	case b == '?':
+		return keyHint, 0
+	case b >= '1' && b <= '9':
+		return keyDigit, int(b - '1')
+	case b == 'k' || b == 'w':
+		return keyUp, 0
+	case b == 'j' || b == 's':
+		return keyDown, 0
+	case b == 'h' || b == 'a':
+		return keyLeft, 0
+	case b == 'l' || b == 'd':
+		return keyRight, 0
+	case b == 27:
+		if next, err := r.ReadByte(); err != nil || next != '[' {
+			return keyNone, 0
+		}
+		arrow, err := r.ReadByte()
+		if err != nil {
+			return keyNone, 0
+		}
+		switch arrow {
+		case 'A':
+			return keyUp, 0
+		case 'B':
+			return keyDown, 0
+		case 'C':
+			return keyRight, 0
+		case 'D':
+			return keyLeft, 0
+		}
+	}
+	return keyNone, 0
+}
+
+func markSymbol(player int) string {
+	switch player {
+	case 1:
+		return "X"
+	case 2:
+		return "O"
+	default:
+		return " "
+	}
+}
+
+// render redraws the whole screen: board, history panel and status bar
+func (g *Game) render(out io.Writer) {
+	var sb strings.Builder
+	sb.WriteString("\033[H\033[2J")
+
+	winning := make(map[int]bool)
+	for _, pos := range g.board.WinningLine() {
+		winning[pos] = true
+	}
+	lastMove := -1
+	if len(g.history) > 0 {
+		lastMove = g.history[len(g.history)-1].Position
+	}
+	over, winner := g.board.IsGameOver()
+
+	var boardLines []string
+	for row := 0; row < 3; row++ {
+		var line strings.Builder
+		for col := 0; col < 3; col++ {
+			pos := row*3 + col
+			cell := " " + markSymbol(g.board.GetCell(pos)) + " "
+			if g.board.IsEmpty(pos) {
+				cell = fmt.Sprintf(" \033[2m%d\033[0m ", pos+1)
+			}
+			switch {
+			case winning[pos]:
+				cell = "\033[1;32m" + cell + "\033[0m"
+			case pos == g.cursor && !over:
+				cell = "\033[7m" + cell + "\033[0m"
+			case pos == lastMove:
+				cell = "\033[1;33m" + cell + "\033[0m"
+			}
+			line.WriteString(cell)
+			if col < 2 {
+				line.WriteString("|")
+			}
+		}
+		boardLines = append(boardLines, line.String())
+		if row < 2 {
+			boardLines = append(boardLines, "---+---+---")
+		}
+	}
+
+	historyLines := []string{"Moves:"}
+	for i, m := range g.history {
+		who := "You"
+		if m.Player != g.HumanPlayer {
+			who = "Agent"
+		}
+		historyLines = append(historyLines, fmt.Sprintf("%2d. %-5s %s at %d", i+1, who, markSymbol(m.Player), m.Position+1))
+	}
+
+	sb.WriteString(fmt.Sprintf("  You are %s\r\n\r\n", markSymbol(g.HumanPlayer)))
+	rows := len(boardLines)
+	if len(historyLines) > rows {
+		rows = len(historyLines)
+	}
+	for i := 0; i < rows; i++ {
+		left := strings.Repeat(" ", 11)
+		if i < len(boardLines) {
+			left = boardLines[i]
+		}
+		right := ""
+		if i < len(historyLines) {
+			right = historyLines[i]
+		}
+		sb.WriteString("  " + left + "     " + right + "\r\n")
+	}
+
+	status := g.message
+	if over {
+		switch winner {
+		case g.HumanPlayer:
+			status = "You win!"
+		case 0:
+			status = "It's a draw!"
+		default:
+			status = "Agent wins!"
+		}
+	}
+	evaluation := ""
+	if g.Evaluate != nil {
+		evaluation = "Agent eval: " + g.Evaluate(g.board, g.agentPlayer())
+	}
+	sb.WriteString("\r\n\033[7m " + fmt.Sprintf("%-30s %s", status, evaluation) + " \033[0m\r\n")
+	sb.WriteString("  arrows move  enter/1-9 place  u undo  ? hint  q quit\r\n")
+	if g.hint != "" {
+		sb.WriteString("\r\n" + strings.ReplaceAll(g.hint, "\n", "\r\n"))
+	}
+
+	fmt.Fprint(out, sb.String())
+}
@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jpotts18/tictactoe/agent"
+	"github.com/jpotts18/tictactoe/game"
+)
+
+func TestPlay(t *testing.T) {
+	tests := []struct {
+		name        string
+		humanPlayer int
+		humanFirst  bool
+		keys        string
+		wantWinner  int
+		wantHistory int
+	}{
+		{
+			name:        "arrow keys and enter",
+			humanPlayer: 1,
+			humanFirst:  true,
+			keys:        "\r" + "\033[B\033[C\r" + "q", // center, then bottom-right
+			wantWinner:  0,
+			wantHistory: 4,
+		},
+		{
+			name:        "agent moves first",
+			humanPlayer: 2,
+			humanFirst:  false,
+			keys:        "q",
+			wantWinner:  0,
+			wantHistory: 1,
+		},
+		{
+			name:        "undo takes back both moves",
+			humanPlayer: 1,
+			humanFirst:  true,
+			keys:        "5u" + "q",
+			wantWinner:  0,
+			wantHistory: 0,
+		},
+		{
+			name:        "ctrl-c quits",
+			humanPlayer: 1,
+			humanFirst:  true,
+			keys:        "5\x03",
+			wantWinner:  0,
+			wantHistory: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Game{
+				Opponent:    agent.NewHeuristicAgent(tt.humanPlayer%2 + 1),
+				HumanPlayer: tt.humanPlayer,
+				HumanFirst:  tt.humanFirst,
+			}
+			if winner := g.play(strings.NewReader(tt.keys), io.Discard); winner != tt.wantWinner {
+				t.Errorf("play() winner = %v, want %v", winner, tt.wantWinner)
+			}
+			if len(g.history) != tt.wantHistory {
+				t.Errorf("history has %d moves, want %d: %v", len(g.history), tt.wantHistory, g.history)
+			}
+		})
+	}
+}
+
+func TestPlayToEnd(t *testing.T) {
+	// Keys for taken squares are ignored; the game must still finish and the
+	// heuristic agent must not lose
+	g := &Game{
+		Opponent:    agent.NewHeuristicAgent(2),
+		HumanPlayer: 1,
+		HumanFirst:  true,
+	}
+	var out strings.Builder
+	winner := g.play(strings.NewReader("1937846"), &out)
+
+	if over, _ := g.board.IsGameOver(); !over {
+		t.Fatalf("game not over after all keys, history %v", g.history)
+	}
+	if winner == 1 {
+		t.Errorf("play() winner = %v, the heuristic agent should never lose", winner)
+	}
+	if !strings.Contains(out.String(), "Agent wins!") && !strings.Contains(out.String(), "It's a draw!") {
+		t.Errorf("final screen does not report the result")
+	}
+}
+
+func TestHintToggle(t *testing.T) {
+	g := &Game{
+		Opponent:    agent.NewHeuristicAgent(2),
+		HumanPlayer: 1,
+		HumanFirst:  true,
+		Hint: func(board *game.Board, player int) string {
+			return "HINT TABLE\n"
+		},
+	}
+	var out strings.Builder
+	g.play(strings.NewReader("?"+"q"), &out)
+	if !strings.Contains(out.String(), "HINT TABLE\r\n") {
+		t.Errorf("hint not rendered after '?'")
+	}
+	if g.hint != "HINT TABLE\n" {
+		t.Errorf("hint = %q, want it kept until the next move", g.hint)
+	}
+}